@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// test 生成对比测试：零值及随机填充的值分别用生成的方法和 Validator.Struct 校验，错误的路径、标题、规则和信息应一致
+func (g *generator) test(names []string) []byte {
+	var w bytes.Buffer
+	fmt.Fprintf(&w, "// Code generated by validatorsgen. DO NOT EDIT.\n\npackage %s\n\n", g.pkg)
+	fmt.Fprintf(&w, "import (\n\"fmt\"\n\"math/rand\"\n\"reflect\"\n\"sort\"\n\"testing\"\n\n%q\n)\n\n", importPath)
+	fmt.Fprintf(&w, "func TestValidatorsgen(t *testing.T) {\n")
+	fmt.Fprintf(&w, "r := rand.New(rand.NewSource(1))\n")
+	for _, name := range names {
+		fmt.Fprintf(&w, "for i := 0; i < 200; i++ {\n")
+		fmt.Fprintf(&w, "s := &%s{}\n", name)
+		fmt.Fprintf(&w, "if i > 0 {\nvalidatorsgenFill(r, reflect.ValueOf(s).Elem(), 0)\n}\n")
+		fmt.Fprintf(&w, "validatorsgenCompare(t, %q, s, func() []error {\nreturn s.validatorsgen(false, \"\")\n})\n", name)
+		fmt.Fprintf(&w, "}\n")
+	}
+	fmt.Fprintf(&w, "}\n\n")
+	// 辅助函数中的 Validator 替换为生成代码使用的变量
+	w.WriteString(strings.Replace(crossCheckHelpers, "validatorsgenValidator", g.validatorName(), -1))
+	return w.Bytes()
+}
+
+const crossCheckHelpers = `func validatorsgenCompare(t *testing.T, name string, s interface{}, generated func() []error) {
+	want, wantPanic := validatorsgenRun(func() []error {
+		return validatorsgenValidator.Struct(s)
+	})
+	got, gotPanic := validatorsgenRun(generated)
+	if (wantPanic == nil) != (gotPanic == nil) {
+		t.Errorf("%s %+v: reflective panic %v, generated panic %v", name, s, wantPanic, gotPanic)
+		return
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s %+v:\nreflective %q\ngenerated  %q", name, s, want, got)
+	}
+}
+
+// validatorsgenRun 将错误格式化为路径、标题、规则和信息并排序，map 中的结构体顺序不固定
+func validatorsgenRun(fn func() []error) (errs []string, recovered interface{}) {
+	defer func() {
+		recovered = recover()
+	}()
+	for _, err := range fn() {
+		if fe, ok := err.(*validators.FieldError); ok {
+			errs = append(errs, fmt.Sprintf("%s %s %s %q: %s", fe.Path, fe.Title, fe.Rule, fe.Checks, fe.Msg))
+			continue
+		}
+		errs = append(errs, fmt.Sprintf("%T: %s", err, err))
+	}
+	sort.Strings(errs)
+	return
+}
+
+var validatorsgenRunes = []rune("aZ09 @.-_中")
+
+// validatorsgenFill 随机填充可导出的字段
+func validatorsgenFill(r *rand.Rand, v reflect.Value, depth int) {
+	if !v.CanSet() || depth > 3 {
+		return
+	}
+	switch v.Kind() {
+	case reflect.String:
+		rs := make([]rune, r.Intn(8))
+		for i := range rs {
+			rs[i] = validatorsgenRunes[r.Intn(len(validatorsgenRunes))]
+		}
+		v.SetString(string(rs))
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+	// 数值有一半取较小的值，便于覆盖规则参数附近的边界
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if r.Intn(2) == 0 {
+			v.SetInt(int64(r.Intn(5) - 1))
+		} else {
+			v.SetInt(int64(r.Intn(300) - 50))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if r.Intn(2) == 0 {
+			v.SetUint(uint64(r.Intn(4)))
+		} else {
+			v.SetUint(uint64(r.Intn(300)))
+		}
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(r.Float64()*300 - 50)
+	case reflect.Slice:
+		n := r.Intn(4)
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validatorsgenFill(r, v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		for i := r.Intn(3); i > 0; i-- {
+			key := reflect.New(v.Type().Key()).Elem()
+			val := reflect.New(v.Type().Elem()).Elem()
+			validatorsgenFill(r, key, depth+1)
+			validatorsgenFill(r, val, depth+1)
+			v.SetMapIndex(key, val)
+		}
+	case reflect.Ptr:
+		if r.Intn(2) == 0 {
+			v.Set(reflect.New(v.Type().Elem()))
+			validatorsgenFill(r, v.Elem(), depth+1)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			validatorsgenFill(r, v.Field(i), depth+1)
+		}
+	}
+}
+`
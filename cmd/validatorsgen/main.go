@@ -0,0 +1,471 @@
+// validatorsgen 按 validate tag 为结构体生成 Validate() error 方法，直接访问字段，不再遍历结构体和读取 tag
+// 基础类型、切片及 map 字段上由 ";" 连接的内置规则(required、len、min、max、lt、gt、in、内联 regex、email、uuid 等)
+// 生成直接比较字段的代码；其余规则(自定义规则、组合规则、命名正则等)及嵌套结构体仍交给 Validator 在运行时校验
+//
+//	//go:generate validatorsgen -output validators_gen.go
+//
+// 生成的方法返回与 Validator.LazyValidate 相同的 FieldError，规则在生成时从 tag 读取，
+// RegisterStructRules 及 LoadRules 配置的规则不生效，RegisterValidator 覆盖或 RegisterAlias 同名的内置规则对直接校验的字段不生效；-test 同时生成对比生成代码与 Validator.Struct 结果的测试
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const importPath = "github.com/ilaorou/validators"
+
+// config 命令行参数
+type config struct {
+	dir       string
+	output    string
+	validTag  string
+	titleTag  string
+	validator string // 生成代码使用的 *validators.Validator 变量，为空时生成 validatorsgenValidator
+	test      bool
+}
+
+func main() {
+	cfg := config{}
+	flag.StringVar(&cfg.dir, "dir", ".", "package directory")
+	flag.StringVar(&cfg.output, "output", "validators_gen.go", "output file name")
+	flag.StringVar(&cfg.validTag, "tag", "validate", "validate tag")
+	flag.StringVar(&cfg.titleTag, "title", "title", "title tag")
+	flag.StringVar(&cfg.validator, "validator", "", "package level *validators.Validator variable used by generated code")
+	flag.BoolVar(&cfg.test, "test", false, "also generate a test comparing generated and reflective results")
+	flag.Parse()
+
+	code, test, err := generate(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "validatorsgen:", err)
+		os.Exit(1)
+	}
+	if err = ioutil.WriteFile(filepath.Join(cfg.dir, cfg.output), code, 0644); err == nil && cfg.test {
+		err = ioutil.WriteFile(filepath.Join(cfg.dir, testFileName(cfg.output)), test, 0644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "validatorsgen:", err)
+		os.Exit(1)
+	}
+}
+
+func testFileName(output string) string {
+	return strings.TrimSuffix(output, ".go") + "_test.go"
+}
+
+// 字段中结构体的校验方式
+const (
+	nestedNone   = iota // 不需要校验
+	nestedMethod        // 调用生成的方法
+	nestedSlice         // 逐个元素调用生成的方法
+	nestedLookup        // 交给 Validator.Nested 按运行时的方式校验
+)
+
+// basicZero 基础类型的零值，用于跳过未填写的字段
+var basicZero = map[string]string{
+	"string": `""`, "bool": "false",
+	"int": "0", "int8": "0", "int16": "0", "int32": "0", "int64": "0", "rune": "0",
+	"uint": "0", "uint8": "0", "uint16": "0", "uint32": "0", "uint64": "0", "byte": "0", "uintptr": "0",
+	"float32": "0", "float64": "0",
+}
+
+// generator 解析包并生成代码
+type generator struct {
+	cfg         config
+	pkg         string
+	types       map[string]ast.Expr // 包中声明的类型
+	order       []string            // 结构体按声明顺序
+	needs       map[string]bool     // 结构体是否需要生成方法
+	visit       map[string]bool
+	rules       []ruleVar
+	patterns    []ruleVar // 内联正则，rule 为正则
+	usesStrconv bool
+	usesUTF8    bool
+}
+
+// ruleVar 字段规则变量
+type ruleVar struct {
+	name  string
+	title string
+	rule  string
+}
+
+func generate(cfg config) (code []byte, test []byte, err error) {
+	fset := token.NewFileSet()
+	skip := func(fi os.FileInfo) bool {
+		name := fi.Name()
+		return !strings.HasSuffix(name, "_test.go") && name != cfg.output
+	}
+	pkgs, err := parser.ParseDir(fset, cfg.dir, skip, 0)
+	if err != nil {
+		return
+	}
+	if len(pkgs) != 1 {
+		return nil, nil, fmt.Errorf("expected one package in %s, found %d", cfg.dir, len(pkgs))
+	}
+	g := &generator{cfg: cfg, types: make(map[string]ast.Expr), needs: make(map[string]bool), visit: make(map[string]bool)}
+	for name, pkg := range pkgs {
+		g.pkg = name
+		files := make([]string, 0, len(pkg.Files))
+		for file := range pkg.Files {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			g.collect(pkg.Files[file])
+		}
+	}
+
+	var body bytes.Buffer
+	var names []string
+	for _, name := range g.order {
+		if !g.need(name) {
+			continue
+		}
+		names = append(names, name)
+		if err = g.method(&body, name); err != nil {
+			return
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil, fmt.Errorf("no struct with %q tags in %s", cfg.validTag, cfg.dir)
+	}
+	if code, err = format.Source(g.header(body.Bytes())); err != nil {
+		return
+	}
+	if cfg.test {
+		test, err = format.Source(g.test(names))
+	}
+	return
+}
+
+// collect 记录文件中声明的类型
+func (g *generator) collect(file *ast.File) {
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			g.types[ts.Name.Name] = ts.Type
+			if _, ok := ts.Type.(*ast.StructType); ok {
+				g.order = append(g.order, ts.Name.Name)
+			}
+		}
+	}
+}
+
+// need 结构体是否有规则或包含需要校验的结构体
+func (g *generator) need(name string) bool {
+	if need, ok := g.needs[name]; ok {
+		return need
+	}
+	// 递归类型展开中按不需要处理
+	if g.visit[name] {
+		return false
+	}
+	g.visit[name] = true
+	defer delete(g.visit, name)
+	st := g.types[name].(*ast.StructType)
+	need := false
+	if len(st.Fields.List) > 0 {
+		for _, field := range st.Fields.List {
+			if g.fieldRule(field) != "" || g.nested(field.Type) != nestedNone {
+				need = true
+				break
+			}
+		}
+	}
+	g.needs[name] = need
+	return need
+}
+
+// localStruct 包中声明且有字段的结构体名
+func (g *generator) localStruct(expr ast.Expr) (string, bool) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	st, ok := g.types[ident.Name].(*ast.StructType)
+	if !ok || len(st.Fields.List) == 0 {
+		return "", false
+	}
+	return ident.Name, true
+}
+
+// underlying 包中声明的类型展开为底层类型
+func (g *generator) underlying(expr ast.Expr) ast.Expr {
+	for i := 0; i < 10; i++ {
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			return expr
+		}
+		next, ok := g.types[ident.Name]
+		if !ok {
+			return expr
+		}
+		if _, ok := next.(*ast.StructType); ok {
+			return expr
+		}
+		expr = next
+	}
+	return expr
+}
+
+// nested 字段中结构体的校验方式，与 Validator 递归校验的规则一致：
+// 结构体字段，以及元素为结构体、数组、map 的数组和 map
+func (g *generator) nested(expr ast.Expr) int {
+	if name, ok := g.localStruct(expr); ok {
+		if g.need(name) {
+			return nestedMethod
+		}
+		return nestedNone
+	}
+	switch t := g.underlying(expr).(type) {
+	case *ast.Ident:
+		if _, ok := g.types[t.Name]; ok {
+			// 没有字段的结构体
+			return nestedLookup
+		}
+		return nestedNone
+	case *ast.StarExpr, *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+		return nestedNone
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "time" {
+			return nestedNone
+		}
+		return nestedLookup
+	case *ast.ArrayType:
+		if name, ok := g.localStruct(t.Elt); ok {
+			if g.need(name) {
+				return nestedSlice
+			}
+			return nestedNone
+		}
+		if g.plainElem(t.Elt) {
+			return nestedNone
+		}
+		return nestedLookup
+	case *ast.MapType:
+		if name, ok := g.localStruct(t.Value); ok && !g.need(name) {
+			return nestedNone
+		}
+		if g.plainElem(t.Value) {
+			return nestedNone
+		}
+		return nestedLookup
+	}
+	return nestedLookup
+}
+
+// plainElem 元素不是结构体、数组或 map，Validator 不递归校验
+func (g *generator) plainElem(expr ast.Expr) bool {
+	switch t := g.underlying(expr).(type) {
+	case *ast.Ident:
+		_, ok := basicZero[t.Name]
+		return ok || t.Name == "error"
+	case *ast.StarExpr, *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+		return true
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "time" && t.Sel.Name != "Time" {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) tag(field *ast.Field, key string) string {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag).Get(key)
+}
+
+func (g *generator) fieldRule(field *ast.Field) string {
+	return g.tag(field, g.cfg.validTag)
+}
+
+// fieldNames 字段名，匿名字段取类型名
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				names = append(names, name.Name)
+			}
+		}
+		return names
+	}
+	expr := field.Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return []string{t.Name}
+	case *ast.SelectorExpr:
+		return []string{t.Sel.Name}
+	}
+	return nil
+}
+
+func (g *generator) validatorName() string {
+	if g.cfg.validator != "" {
+		return g.cfg.validator
+	}
+	return "validatorsgenValidator"
+}
+
+// method 生成结构体的校验方法
+func (g *generator) method(w *bytes.Buffer, name string) error {
+	v := g.validatorName()
+	fmt.Fprintf(w, "// Validate 校验 %s，返回第一个错误\n", name)
+	fmt.Fprintf(w, "func (s *%s) Validate() error {\n", name)
+	fmt.Fprintf(w, "if errs := s.validatorsgen(true, \"\"); len(errs) > 0 {\nreturn errs[0]\n}\nreturn nil\n}\n\n")
+	fmt.Fprintf(w, "func (s *%s) validatorsgen(lazy bool, path string) (errs []error) {\n", name)
+	st := g.types[name].(*ast.StructType)
+	for _, field := range st.Fields.List {
+		rule := g.fieldRule(field)
+		nested := g.nested(field.Type)
+		for _, fieldName := range fieldNames(field) {
+			if rule == "" && nested == nestedNone {
+				continue
+			}
+			fmt.Fprintf(w, "// %s\n", fieldName)
+			if rule == "" {
+				g.nestedCode(w, field, fieldName, nested)
+				continue
+			}
+			title := g.tag(field, g.cfg.titleTag)
+			if title == "" {
+				title = fieldName
+			}
+			ruleName := "validatorsgen" + name + fieldName
+			g.rules = append(g.rules, ruleVar{name: ruleName, title: title, rule: rule})
+			path := fmt.Sprintf("validatorsgenJoin(path, %q)", fieldName)
+			f := typedField{expr: "s." + fieldName, kind: g.typedKind(field.Type), title: strconv.Quote(title)}
+			if checks, ok := g.typedChecks(f, rule, ruleName); ok {
+				g.typedCode(w, f, checks, ruleName, path)
+				if nested != nestedNone {
+					fmt.Fprintf(w, "} else {\n")
+					g.nestedCode(w, field, fieldName, nested)
+				}
+				fmt.Fprintf(w, "}\n}\n")
+				continue
+			}
+			if ident, ok := field.Type.(*ast.Ident); ok && basicZero[ident.Name] != "" {
+				// 基础类型的零值直接跳过，不必装箱
+				fmt.Fprintf(w, "if s.%s != %s || %s.CheckZero(%s) {\n", fieldName, basicZero[ident.Name], ruleName, v)
+				fmt.Fprintf(w, "if errArr, _ := %s.Check(%s, s, &s.%s, %s); len(errArr) > 0 {\n", ruleName, v, fieldName, path)
+				fmt.Fprintf(w, "errs = append(errs, errArr...)\nif lazy {\nreturn\n}\n}\n}\n")
+				continue
+			}
+			if nested == nestedNone {
+				fmt.Fprintf(w, "if errArr, _ := %s.Check(%s, s, &s.%s, %s); len(errArr) > 0 {\n", ruleName, v, fieldName, path)
+				fmt.Fprintf(w, "errs = append(errs, errArr...)\nif lazy {\nreturn\n}\n}\n")
+				continue
+			}
+			fmt.Fprintf(w, "if errArr, next := %s.Check(%s, s, &s.%s, %s); len(errArr) > 0 {\n", ruleName, v, fieldName, path)
+			fmt.Fprintf(w, "errs = append(errs, errArr...)\nif lazy {\nreturn\n}\n")
+			fmt.Fprintf(w, "} else if next {\n")
+			g.nestedCode(w, field, fieldName, nested)
+			fmt.Fprintf(w, "}\n")
+		}
+	}
+	fmt.Fprintf(w, "return\n}\n\n")
+	return nil
+}
+
+// typedCode 直接校验字段，零值的处理及同一字段规则的执行顺序与 FieldRule.Check 一致
+// 生成的代码以字段未通过时的 if 结束，由调用方补充通过时的代码及结尾的括号
+func (g *generator) typedCode(w *bytes.Buffer, f typedField, checks []typedCheck, ruleName string, path string) {
+	v := g.validatorName()
+	zero := f.expr + " != 0"
+	switch f.kind {
+	case typedString:
+		zero = f.expr + ` != ""`
+	case typedBool:
+		zero = f.expr
+	case typedLen:
+		zero = fmt.Sprintf("len(%s) != 0", f.expr)
+	}
+	fmt.Fprintf(w, "if %s || %s.CheckZero(%s) {\n", zero, ruleName, v)
+	fmt.Fprintf(w, "var errArr []error\n")
+	for i, check := range checks {
+		cond := check.cond
+		if i > 0 {
+			cond = fmt.Sprintf("(len(errArr) == 0 || %s.Continue(%s)) && (%s)", ruleName, v, cond)
+		}
+		fmt.Fprintf(w, "if %s {\n", cond)
+		fmt.Fprintf(w, "errArr = append(errArr, %s.Fail(%s, %q, validators.%s, %s))\n", ruleName, path, check.rule, check.key, strings.Join(check.args, ", "))
+		fmt.Fprintf(w, "}\n")
+	}
+	fmt.Fprintf(w, "if len(errArr) > 0 {\nerrs = append(errs, errArr...)\nif lazy {\nreturn\n}\n")
+}
+
+// nestedCode 校验字段中的结构体
+func (g *generator) nestedCode(w *bytes.Buffer, field *ast.Field, fieldName string, nested int) {
+	path := fmt.Sprintf("validatorsgenJoin(path, %q)", fieldName)
+	switch nested {
+	case nestedMethod:
+		fmt.Fprintf(w, "if errArr := s.%s.validatorsgen(lazy, %s); len(errArr) > 0 {\n", fieldName, path)
+	case nestedSlice:
+		g.usesStrconv = true
+		fmt.Fprintf(w, "for i := range s.%s {\n", fieldName)
+		fmt.Fprintf(w, "if errArr := s.%s[i].validatorsgen(lazy, validatorsgenJoin(%s, strconv.Itoa(i))); len(errArr) > 0 {\n", fieldName, path)
+		fmt.Fprintf(w, "errs = append(errs, errArr...)\nif lazy {\nreturn\n}\n}\n}\n")
+		return
+	default:
+		fmt.Fprintf(w, "if errArr := %s.Nested(&s.%s, lazy, %s); len(errArr) > 0 {\n", g.validatorName(), fieldName, path)
+	}
+	fmt.Fprintf(w, "errs = append(errs, errArr...)\nif lazy {\nreturn\n}\n}\n")
+}
+
+// header 文件头、规则变量及辅助函数
+func (g *generator) header(body []byte) []byte {
+	var w bytes.Buffer
+	fmt.Fprintf(&w, "// Code generated by validatorsgen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", g.pkg)
+	if len(g.patterns) > 0 {
+		fmt.Fprintf(&w, "%q\n", "regexp")
+	}
+	if g.usesStrconv {
+		fmt.Fprintf(&w, "%q\n", "strconv")
+	}
+	if g.usesUTF8 {
+		fmt.Fprintf(&w, "%q\n", "unicode/utf8")
+	}
+	fmt.Fprintf(&w, "\n%q\n)\n\n", importPath)
+	if g.cfg.validator == "" {
+		fmt.Fprintf(&w, "// validatorsgenValidator 生成的校验方法使用的 Validator\n")
+		fmt.Fprintf(&w, "var validatorsgenValidator = validators.New()\n\n")
+	}
+	fmt.Fprintf(&w, "var (\n")
+	for _, r := range g.rules {
+		fmt.Fprintf(&w, "%s = validators.NewFieldRule(%s, %s)\n", r.name, strconv.Quote(r.title), strconv.Quote(r.rule))
+	}
+	for _, p := range g.patterns {
+		fmt.Fprintf(&w, "%s = regexp.MustCompile(%s)\n", p.name, strconv.Quote(p.rule))
+	}
+	fmt.Fprintf(&w, ")\n\n")
+	w.Write(body)
+	fmt.Fprintf(&w, "func validatorsgenJoin(parent string, name string) string {\n")
+	fmt.Fprintf(&w, "if parent == \"\" {\nreturn name\n}\nreturn parent + validators.VALIDATOR_PATH_SPLIT + name\n}\n")
+	return w.Bytes()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const exampleSource = `package example
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Base struct {
+	ID string ` + "`" + `validate:"required;uuid"` + "`" + `
+}
+
+type Status string
+
+type Address struct {
+	City string ` + "`" + `validate:"required;len=1,5" title:"城市"` + "`" + `
+	Zip  string ` + "`" + `validate:"number|len=6,6"` + "`" + `
+}
+
+type Item struct {
+	Sku   string  ` + "`" + `validate:"required;startswith=SKU"` + "`" + `
+	Count int     ` + "`" + `validate:"gte=1"` + "`" + `
+	Code  string  ` + "`" + `validate:"regex=/^[a-z0-9]+$/;len_bytes=_,6"` + "`" + `
+	Level int8    ` + "`" + `validate:"in=1,2,010"` + "`" + `
+	Kind  string  ` + "`" + `validate:"required;in=a,Z0"` + "`" + `
+	Qty   uint    ` + "`" + `validate:"min=1;max=0x20;len=_,100"` + "`" + `
+	Price float64 ` + "`" + `validate:"gt=0;lt=1e2;len=1.5,_"` + "`" + `
+	Mail  string  ` + "`" + `validate:"email"` + "`" + `
+	Short string  ` + "`" + `validate:"lt=3;min=1"` + "`" + `
+	Flags map[string]bool ` + "`" + `validate:"required;max=1"` + "`" + `
+}
+
+type Order struct {
+	Base
+	Name      string            ` + "`" + `validate:"required;len=2,10" title:"名称"` + "`" + `
+	Status    Status            ` + "`" + `validate:"in=new,paid"` + "`" + `
+	Email     string            ` + "`" + `validate:"email|phone"` + "`" + `
+	Min       int               ` + "`" + `validate:"lte=100"` + "`" + `
+	Max       int               ` + "`" + `validate:"expr=Max >= Min"` + "`" + `
+	Tags      []string          ` + "`" + `validate:"unique;len=_,3"` + "`" + `
+	Address   Address
+	Items     []Item            ` + "`" + `validate:"len=_,2"` + "`" + `
+	Extra     map[string]Item
+	Note      sql.NullString    ` + "`" + `validate:"required"` + "`" + `
+	CreatedAt time.Time
+	Parent    *Order
+}
+`
+
+func TestGenerate(t *testing.T) {
+	if err := os.MkdirAll("testdata", 0755); err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("testdata", "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = ioutil.WriteFile(filepath.Join(dir, "example.go"), []byte(exampleSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{dir: dir, output: "validators_gen.go", validTag: "validate", titleTag: "title", test: true}
+	code, test, err := generate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"func (s *Order) Validate() error",
+		"func (s *Address) validatorsgen(lazy bool, path string)",
+		`validators.NewFieldRule("名称", "required;len=2,10")`,
+		// 内置规则直接比较字段，组合规则及自定义类型交给运行时
+		`if s.Name == "" {`,
+		"int64(utf8.RuneCountInString(s.Name)) < 2 || int64(utf8.RuneCountInString(s.Name)) > 10",
+		"!validators.IsUUID(s.ID)",
+		"!validatorsgenItemCodeRegex0.MatchString(s.Code)",
+		"validatorsgenOrderEmail.Check(validatorsgenValidator, s, &s.Email",
+		"validatorsgenOrderStatus.Check(validatorsgenValidator, s, &s.Status",
+		"validatorsgenOrderNote.Check(validatorsgenValidator, s, &s.Note",
+		"s.Address.validatorsgen(lazy",
+		"s.Items[i].validatorsgen(lazy",
+		"validatorsgenValidator.Nested(&s.Extra",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Expected generated code to contain %s,got\n%s", want, code)
+		}
+	}
+	ioutil.WriteFile(filepath.Join(dir, cfg.output), code, 0644)
+	ioutil.WriteFile(filepath.Join(dir, testFileName(cfg.output)), test, 0644)
+
+	// 运行生成的对比测试
+	out, err := exec.Command("go", "test", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	if err != nil {
+		t.Errorf("Expected generated test to pass,got %v\n%s", err, out)
+	}
+}
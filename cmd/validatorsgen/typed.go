@@ -0,0 +1,333 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ilaorou/validators"
+)
+
+// 直接校验的字段类型，只处理预声明的基础类型及切片、map 字面类型，包中声明的类型可能实现了 driver.Valuer 等，交给运行时
+const (
+	typedNone   = iota
+	typedString // string
+	typedInt    // 有符号整数
+	typedUint   // 无符号整数
+	typedFloat  // 浮点数
+	typedBool   // bool
+	typedLen    // 切片、map，规则作用于长度
+)
+
+var typedIdents = map[string]int{
+	"string": typedString, "bool": typedBool,
+	"int": typedInt, "int8": typedInt, "int16": typedInt, "int32": typedInt, "int64": typedInt, "rune": typedInt,
+	"uint": typedUint, "uint8": typedUint, "uint16": typedUint, "uint32": typedUint, "uint64": typedUint, "byte": typedUint,
+	"float32": typedFloat, "float64": typedFloat,
+}
+
+// 比较规则未通过的条件，min、max 同 gte、lte
+var typedCompare = map[string]struct {
+	fail   string
+	length string // 字符串、切片等比较长度时的错误信息
+	number string // 比较数值时的错误信息
+}{
+	"lt":  {">=", "ValidLengthLt", "ValidDecimalLt"},
+	"lte": {">", "ValidLengthLte", "ValidDecimalLte"},
+	"max": {">", "ValidLengthLte", "ValidDecimalLte"},
+	"gt":  {"<=", "ValidLengthGt", "ValidDecimalGt"},
+	"gte": {"<", "ValidLengthGte", "ValidDecimalGte"},
+	"min": {"<", "ValidLengthGte", "ValidDecimalGte"},
+}
+
+// len 规则的错误信息，依次为等于、范围、下限、上限，与 Validator 一致
+var (
+	typedLengthMessages = [4]string{"ValidLengthEq", "ValidLength", "ValidLengthGte", "ValidLengthLte"}
+	typedRangeMessages  = [4]string{"ValidDecimalEq", "ValidBetween", "ValidDecimalGte", "ValidDecimalLte"}
+)
+
+// typedField 直接校验的字段，expr 为字段表达式(如 s.Name)，title 为带引号的标题
+type typedField struct {
+	expr  string
+	kind  int
+	title string
+}
+
+// typedCheck 单条规则生成的代码，cond 成立时规则未通过，args 为错误信息的参数
+// 规则均可直接校验时才记录用到的 unicode/utf8 及内联正则
+type typedCheck struct {
+	rule    string
+	cond    string
+	key     string
+	args    []string
+	utf8    bool
+	pattern *ruleVar
+}
+
+// typedKind 字段类型对应的直接校验方式
+func (g *generator) typedKind(expr ast.Expr) int {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		// 包中重新声明了同名类型时不处理
+		if _, ok := g.types[t.Name]; ok {
+			return typedNone
+		}
+		return typedIdents[t.Name]
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return typedLen
+		}
+	case *ast.MapType:
+		return typedLen
+	}
+	return typedNone
+}
+
+// typedChecks 规则均为可直接校验的内置规则时返回各条规则的代码，否则 ok 为 false，由 FieldRule.Check 校验
+func (g *generator) typedChecks(f typedField, rule string, ruleName string) (checks []typedCheck, ok bool) {
+	if f.kind == typedNone {
+		return nil, false
+	}
+	steps, ok := validators.RuleSteps(rule)
+	if !ok {
+		return nil, false
+	}
+	for i, step := range steps {
+		check, ok := g.typedStep(f, step, fmt.Sprintf("%sRegex%d", ruleName, i))
+		if !ok {
+			return nil, false
+		}
+		if check.cond != "" {
+			checks = append(checks, check)
+		}
+	}
+	for _, check := range checks {
+		g.usesUTF8 = g.usesUTF8 || check.utf8
+		if check.pattern != nil {
+			g.patterns = append(g.patterns, *check.pattern)
+		}
+	}
+	return checks, true
+}
+
+// typedStep 单条规则的代码，与 validator_func.go 中对应规则的判断一致，参数无法在生成时确定结果的交给运行时
+func (g *generator) typedStep(f typedField, step validators.RuleStep, regexName string) (check typedCheck, ok bool) {
+	field, kind, params := f.expr, f.kind, step.Params
+	check.rule = step.Name
+	check.args = []string{f.title}
+	switch step.Name {
+	case "required":
+		switch kind {
+		case typedLen:
+			check.cond = fmt.Sprintf("len(%s) == 0", field)
+		case typedBool:
+			check.cond = "!" + field
+		case typedString:
+			check.cond = field + ` == ""`
+		default:
+			check.cond = field + " == 0"
+		}
+		check.key = "ValidRequired"
+		return check, true
+	case "email", "uuid":
+		if kind != typedString || len(params) > 0 {
+			return
+		}
+		fn, key := "IsEmail", "ValidIsEmail"
+		if step.Name == "uuid" {
+			fn, key = "IsUUID", "ValidIsUUID"
+		}
+		check.cond = fmt.Sprintf("!validators.%s(%s)", fn, field)
+		check.key = key
+		return check, true
+	case "regex":
+		// 只处理内联正则，命名正则在运行时注册
+		name := strings.Join(params, validators.VALIDATOR_RANGE_SPLIT)
+		if kind != typedString || len(name) < 2 || !strings.HasPrefix(name, "/") || !strings.HasSuffix(name, "/") {
+			return
+		}
+		pattern := name[1 : len(name)-1]
+		if _, err := regexp.Compile(pattern); err != nil {
+			return
+		}
+		check.pattern = &ruleVar{name: regexName, rule: pattern}
+		check.cond = fmt.Sprintf("!%s.MatchString(%s)", regexName, field)
+		check.key = "ValidIsRegex"
+		check.args = append(check.args, strconv.Quote(name))
+		return check, true
+	case "in":
+		return g.typedIn(f, params)
+	case "len", "len_runes", "len_bytes":
+		return g.typedLength(f, step)
+	}
+	cmp, found := typedCompare[step.Name]
+	if !found || len(params) != 1 {
+		return
+	}
+	value, key, literal := "", cmp.number, ""
+	switch kind {
+	case typedString:
+		check.utf8 = true
+		value, key = fmt.Sprintf("int64(utf8.RuneCountInString(%s))", field), cmp.length
+		literal, ok = intLiteral(params[0])
+	case typedLen:
+		value, key = fmt.Sprintf("int64(len(%s))", field), cmp.length
+		literal, ok = intLiteral(params[0])
+	case typedInt:
+		value = fmt.Sprintf("int64(%s)", field)
+		literal, ok = intLiteral(params[0])
+	case typedUint:
+		value = fmt.Sprintf("uint64(%s)", field)
+		literal, ok = uintLiteral(params[0])
+	case typedFloat:
+		value = fmt.Sprintf("float64(%s)", field)
+		literal, ok = floatLiteral(params[0])
+	}
+	if !ok {
+		return
+	}
+	check.cond = fmt.Sprintf("%s %s %s", value, cmp.fail, literal)
+	check.key = key
+	check.args = append(check.args, strconv.Quote(params[0]))
+	return check, true
+}
+
+// typedIn in 规则，只处理字符串和整数，参数为 @枚举名 时在运行时展开
+func (g *generator) typedIn(f typedField, params []string) (check typedCheck, ok bool) {
+	field, kind := f.expr, f.kind
+	check.rule = "in"
+	if len(params) == 0 {
+		return
+	}
+	var conds, quoted []string
+	for _, param := range params {
+		if strings.HasPrefix(param, validators.VALIDATOR_ENUM_SIGN) {
+			return
+		}
+		quoted = append(quoted, strconv.Quote(param))
+		switch kind {
+		case typedString:
+			conds = append(conds, fmt.Sprintf("%s != %s", field, strconv.Quote(param)))
+		case typedInt:
+			i, err := strconv.ParseInt(param, 10, 64)
+			if err != nil {
+				return
+			}
+			conds = append(conds, fmt.Sprintf("int64(%s) != %d", field, i))
+		case typedUint:
+			i, err := strconv.ParseUint(param, 10, 64)
+			if err != nil {
+				return
+			}
+			conds = append(conds, fmt.Sprintf("uint64(%s) != %d", field, i))
+		default:
+			return
+		}
+	}
+	check.cond = strings.Join(conds, " && ")
+	check.key = "ValidIn"
+	// 信息为字段值及全部参数，不含标题
+	check.args = []string{field, "[]string{" + strings.Join(quoted, ", ") + "}"}
+	return check, true
+}
+
+// typedLength len 规则，字符串按字符或字节计算长度，数值为取值范围
+func (g *generator) typedLength(f typedField, step validators.RuleStep) (check typedCheck, ok bool) {
+	field, kind, params := f.expr, f.kind, step.Params
+	check.rule = step.Name
+	if len(params) != 1 && len(params) != 2 {
+		return
+	}
+	value, keys, literal := "", typedRangeMessages, intLiteral
+	switch kind {
+	case typedString:
+		keys = typedLengthMessages
+		if step.Name == "len_bytes" {
+			value = fmt.Sprintf("int64(len(%s))", field)
+		} else {
+			check.utf8 = true
+			value = fmt.Sprintf("int64(utf8.RuneCountInString(%s))", field)
+		}
+	case typedLen:
+		value, keys = fmt.Sprintf("int64(len(%s))", field), typedLengthMessages
+	case typedInt, typedUint:
+		value = fmt.Sprintf("int64(%s)", field)
+	case typedFloat:
+		value, literal = fmt.Sprintf("float64(%s)", field), floatLiteral
+	default:
+		return
+	}
+	if len(params) == 1 {
+		p, ok := literal(params[0])
+		if !ok {
+			return check, false
+		}
+		check.cond = fmt.Sprintf("%s != %s", value, p)
+		check.key = keys[0]
+		check.args = []string{f.title, strconv.Quote(params[0])}
+		return check, true
+	}
+	min, max := params[0], params[1]
+	var conds []string
+	if min != validators.VALIDATOR_IGNORE_SIGN {
+		p, ok := literal(min)
+		if !ok {
+			return check, false
+		}
+		conds = append(conds, fmt.Sprintf("%s < %s", value, p))
+	}
+	if max != validators.VALIDATOR_IGNORE_SIGN {
+		p, ok := literal(max)
+		if !ok {
+			return check, false
+		}
+		conds = append(conds, fmt.Sprintf("%s > %s", value, p))
+	}
+	// 上下限均为 "_" 时不校验
+	check.cond = strings.Join(conds, " || ")
+	switch {
+	case len(conds) == 2:
+		check.key = keys[1]
+		check.args = []string{f.title, strconv.Quote(min), strconv.Quote(max)}
+	case min != validators.VALIDATOR_IGNORE_SIGN:
+		check.key = keys[2]
+		check.args = []string{f.title, strconv.Quote(min)}
+	default:
+		check.key = keys[3]
+		check.args = []string{f.title, strconv.Quote(max)}
+	}
+	return check, true
+}
+
+// 参数转为 Go 字面量，解析方式与运行时的 asInt、asUint、asFloat 一致，运行时会 panic 的参数返回 false
+
+func intLiteral(param string) (string, bool) {
+	i, err := strconv.ParseInt(param, 0, 64)
+	if err != nil {
+		return "", false
+	}
+	return strconv.FormatInt(i, 10), true
+}
+
+func uintLiteral(param string) (string, bool) {
+	i, err := strconv.ParseUint(param, 0, 64)
+	if err != nil {
+		return "", false
+	}
+	return strconv.FormatUint(i, 10), true
+}
+
+func floatLiteral(param string) (string, bool) {
+	f, err := strconv.ParseFloat(param, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", false
+	}
+	literal := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(literal, ".e") {
+		literal += ".0"
+	}
+	return literal, true
+}
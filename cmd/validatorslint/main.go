@@ -0,0 +1,216 @@
+// validatorslint 静态检查结构体 tag 中的校验规则，按 file:line:col 输出问题，有问题时退出码为 1
+//
+//	validatorslint ./...
+//	validatorslint -rules mobile,sku ./api ./model
+//
+// 检查不存在的规则、参数个数、len/min/max 等规则的数字参数，以及规则是否适用于字段类型(如 unique 用于字符串)
+// 自定义规则及别名通过 -rules 声明，只检查是否存在
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ilaorou/validators"
+)
+
+// basicClasses 内置类型的分类
+var basicClasses = map[string]validators.TypeClass{
+	"string": validators.TypeString, "bool": validators.TypeBool,
+	"int": validators.TypeNumber, "int8": validators.TypeNumber, "int16": validators.TypeNumber,
+	"int32": validators.TypeNumber, "int64": validators.TypeNumber, "rune": validators.TypeNumber,
+	"uint": validators.TypeNumber, "uint8": validators.TypeNumber, "uint16": validators.TypeNumber,
+	"uint32": validators.TypeNumber, "uint64": validators.TypeNumber, "byte": validators.TypeNumber,
+	"uintptr": validators.TypeNumber, "float32": validators.TypeNumber, "float64": validators.TypeNumber,
+}
+
+// packageClasses 其他包中已知的类型
+var packageClasses = map[string]validators.TypeClass{
+	"time.Time":     validators.TypeTime,
+	"time.Duration": validators.TypeDuration,
+	"big.Int":       validators.TypeNumber,
+	"big.Rat":       validators.TypeNumber,
+	"big.Float":     validators.TypeNumber,
+}
+
+// linter 检查一个目录中的文件
+type linter struct {
+	v     *validators.Validator
+	tag   string
+	fset  *token.FileSet
+	types map[string]ast.Expr // 目录中声明的类型
+	out   io.Writer
+	count int
+}
+
+func main() {
+	tag := flag.String("tag", "validate", "validate tag")
+	rules := flag.String("rules", "", "comma separated custom rules and aliases registered at runtime")
+	flag.Parse()
+
+	v := validators.New()
+	registerRules(v, *rules)
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	dirs, err := expandDirs(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "validatorslint:", err)
+		os.Exit(2)
+	}
+	count := 0
+	for _, dir := range dirs {
+		n, err := lintDir(v, *tag, dir, os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "validatorslint:", err)
+			os.Exit(2)
+		}
+		count += n
+	}
+	if count > 0 {
+		os.Exit(1)
+	}
+}
+
+// registerRules 注册 -rules 声明的规则，只用于检查规则是否存在
+func registerRules(v *validators.Validator, rules string) {
+	for _, name := range strings.Split(rules, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			v.RegisterValidator(name, func(ft reflect.Type, fv reflect.Value, title string, params ...string) error {
+				return nil
+			})
+		}
+	}
+}
+
+// expandDirs 展开参数中的目录，dir/... 包含全部子目录，跳过 vendor、testdata 及以 . 或 _ 开头的目录
+func expandDirs(args []string) (dirs []string, err error) {
+	for _, arg := range args {
+		if !strings.HasSuffix(arg, "/...") && arg != "..." {
+			dirs = append(dirs, arg)
+			continue
+		}
+		root := strings.TrimSuffix(strings.TrimSuffix(arg, "..."), "/")
+		if root == "" {
+			root = "."
+		}
+		err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || !info.IsDir() {
+				return err
+			}
+			name := info.Name()
+			if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// lintDir 检查目录中的 go 文件，返回问题个数
+func lintDir(v *validators.Validator, tag string, dir string, out io.Writer) (int, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	l := &linter{v: v, tag: tag, fset: token.NewFileSet(), types: make(map[string]ast.Expr), out: out}
+	var files []*ast.File
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") {
+			continue
+		}
+		file, err := parser.ParseFile(l.fset, filepath.Join(dir, info.Name()), nil, 0)
+		if err != nil {
+			return 0, err
+		}
+		files = append(files, file)
+		for _, decl := range file.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					l.types[ts.Name.Name] = ts.Type
+				}
+			}
+		}
+	}
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			if st, ok := node.(*ast.StructType); ok {
+				l.lintStruct(st)
+			}
+			return true
+		})
+	}
+	return l.count, nil
+}
+
+func (l *linter) lintStruct(st *ast.StructType) {
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		rules := reflect.StructTag(tag).Get(l.tag)
+		if rules == "" {
+			continue
+		}
+		pos := l.fset.Position(field.Tag.Pos())
+		for _, err := range l.v.CheckTag(rules, l.class(field.Type, 0)) {
+			fmt.Fprintf(l.out, "%s: %s (%s:%q)\n", pos, err, l.tag, rules)
+			l.count++
+		}
+	}
+}
+
+// class 字段类型的分类，无法确定时为 TypeAny
+func (l *linter) class(expr ast.Expr, depth int) validators.TypeClass {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if class, ok := basicClasses[t.Name]; ok {
+			return class
+		}
+		// 包中声明的结构体可能通过 RegisterCustomTypeFunc 转换，不检查类型
+		if next, ok := l.types[t.Name]; ok && depth < 10 {
+			if _, ok := next.(*ast.StructType); !ok {
+				return l.class(next, depth+1)
+			}
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			return packageClasses[pkg.Name+"."+t.Sel.Name]
+		}
+	case *ast.StarExpr:
+		// 指针只识别 math/big 类型
+		if class := l.class(t.X, depth+1); class == validators.TypeNumber {
+			if _, ok := t.X.(*ast.SelectorExpr); ok {
+				return class
+			}
+		}
+	case *ast.ArrayType:
+		return validators.TypeList
+	case *ast.MapType:
+		return validators.TypeMap
+	case *ast.StructType:
+		return validators.TypeStruct
+	}
+	return validators.TypeAny
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ilaorou/validators"
+)
+
+const exampleSource = `package example
+
+import "time"
+
+type Status string
+
+type Order struct {
+	Name    string    ` + "`" + `validate:"requried;len=1,5"` + "`" + `
+	Code    string    ` + "`" + `validate:"unique"` + "`" + `
+	Age     int       ` + "`" + `validate:"min=abc;max=1,2"` + "`" + `
+	Status  Status    ` + "`" + `validate:"len=x,_"` + "`" + `
+	Created time.Time ` + "`" + `validate:"gte=now-18y;email"` + "`" + `
+	Tags    []string  ` + "`" + `validate:"unique;len=_,3"` + "`" + `
+	Mobile  string    ` + "`" + `validate:"mobile|email"` + "`" + `
+	Items   []struct {
+		Sku string ` + "`" + `validate:"required;(len=1"` + "`" + `
+	}
+	OrderNo string ` + "`" + `validate:"regex=orderNo;phone=XX"` + "`" + `
+}
+`
+
+func TestLint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "validatorslint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = ioutil.WriteFile(filepath.Join(dir, "example.go"), []byte(exampleSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	v := validators.New()
+	var out bytes.Buffer
+	count, err := lintDir(v, "validate", dir, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"example.go:8:20: 校验规则 requried 不存在",
+		"example.go:9:20: 规则unique不适用于string类型的字段",
+		"example.go:10:20: 规则min的参数abc格式有误",
+		"example.go:10:20: 规则max的参数个数应为1，实际为2",
+		"example.go:11:20: 规则len的参数x格式有误",
+		"example.go:12:20: 规则email不适用于time类型的字段",
+		"example.go:14:20: 校验规则 mobile 不存在",
+		"example.go:16:14: ",
+	}
+	// 运行时注册的正则名称及手机号码地区无法得知，不报告
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if count != len(expected) || len(lines) != len(expected) {
+		t.Fatalf("Expected %d problems,got %d\n%s", len(expected), count, out.String())
+	}
+	for i, want := range expected {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("Expected %s,got %s", want, lines[i])
+		}
+	}
+
+	// -rules 声明的规则不再报告
+	registerRules(v, "mobile, sku")
+	out.Reset()
+	if count, _ = lintDir(v, "validate", dir, &out); count != len(expected)-1 || strings.Contains(out.String(), "mobile") {
+		t.Errorf("Expected declared rule mobile to be accepted,got\n%s", out.String())
+	}
+}
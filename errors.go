@@ -0,0 +1,38 @@
+package validators
+
+// FieldError 字段校验错误，Checks 记录未通过的具体检查项
+type FieldError struct {
+	Title      string   // 字段标题
+	Path       string   // 字段路径，如 Items.0.Sku
+	Rule       string   // 校验规则
+	Checks     []string // 未通过的检查项
+	Duplicates []string // 唯一值校验中重复元素的下标或 map 键
+	Msg        string   // 错误信息
+}
+
+func (e *FieldError) Error() string {
+	return e.Msg
+}
+
+// TagError tag 配置有误，如规则格式错误、表达式无法解析
+type TagError struct {
+	Path string // Compile 检查时为字段路径，如 Order.Items.Sku
+	Tag  string // 有误的规则
+	Msg  string // 错误信息
+}
+
+func (e *TagError) Error() string {
+	if e.Path != "" {
+		return e.Path + ": " + e.Msg
+	}
+	return e.Msg
+}
+
+func newFieldError(title string, rule string, msg string, checks ...string) *FieldError {
+	return &FieldError{
+		Title:  title,
+		Rule:   rule,
+		Checks: checks,
+		Msg:    msg,
+	}
+}
@@ -0,0 +1,551 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 简单表达式，用于 expr 规则，在字段所在结构体上求值
+//
+//	expr=Age >= 18 || Guardian != ""
+//	expr=len(Items) <= MaxItems && Address.City != ""
+//
+// 支持字面量(数字、"字符串"、true、false、nil)、字段及嵌套字段、len()、
+// 算术运算 + - * / %、比较运算 == != < <= > >=、逻辑运算 && || !
+// 数字统一按 float64 计算，len 对字符串取字符数
+
+// isExpr 表达式规则，表达式按结构体类型编译并缓存，表达式有误时返回 TagError
+func (v *Validator) isExpr(sv reflect.Value, ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if len(params) != 1 {
+		return fmt.Errorf("参数个数有误")
+	}
+	src := params[0]
+	if sv.Kind() != reflect.Struct {
+		return &TagError{Tag: "expr=" + src, Msg: fmt.Sprintf(trans(ValidExprStruct), src)}
+	}
+	key := exprKey{typ: sv.Type(), src: src}
+	cached, ok := v.exprs.Load(key)
+	if !ok {
+		root, e := compileExpr(src, sv.Type())
+		cached, _ = v.exprs.LoadOrStore(key, &compiledExpr{root: root, err: e})
+	}
+	compiled := cached.(*compiledExpr)
+	if compiled.err != nil {
+		return &TagError{Tag: "expr=" + src, Msg: fmt.Sprintf(trans(ValidExprSyntax), src, compiled.err)}
+	}
+	ok, e := evalExpr(compiled.root, sv)
+	if e != nil {
+		return fmt.Errorf(trans(ValidExprEval), title, src, e)
+	}
+	if !ok {
+		err = fmt.Errorf(trans(ValidExpr), title, src)
+	}
+	return
+}
+
+// exprNode 表达式语法树节点
+type exprNode interface {
+	eval(sv reflect.Value) (interface{}, error)
+}
+
+// compiledExpr 按结构体类型编译后的表达式，err 为编译错误
+type compiledExpr struct {
+	root exprNode
+	err  error
+}
+
+type exprKey struct {
+	typ reflect.Type
+	src string
+}
+
+// compileExpr 按结构体类型编译表达式，字段在编译时解析
+func compileExpr(src string, typ reflect.Type) (exprNode, error) {
+	tokens, err := lexExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens, typ: typ}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("多余的 %q", p.tokens[p.pos].text)
+	}
+	return root, nil
+}
+
+// evalExpr 求值，结果须为 bool
+func evalExpr(root exprNode, sv reflect.Value) (bool, error) {
+	val, err := root.eval(sv)
+	if err != nil {
+		return false, err
+	}
+	b, ok := val.(bool)
+	if !ok {
+		return false, fmt.Errorf("结果不是 bool: %v", val)
+	}
+	return b, nil
+}
+
+// 词法
+
+const (
+	tokenNumber = iota
+	tokenString
+	tokenIdent
+	tokenOp
+)
+
+type exprToken struct {
+	kind int
+	text string
+	val  interface{}
+}
+
+var exprOps = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", ",", "."}
+
+func lexExpr(src string) (tokens []exprToken, err error) {
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r >= '0' && r <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			f, e := strconv.ParseFloat(src[i:j], 64)
+			if e != nil {
+				return nil, fmt.Errorf("数字 %q 有误", src[i:j])
+			}
+			tokens = append(tokens, exprToken{kind: tokenNumber, text: src[i:j], val: f})
+			i = j
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != byte(r) {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("字符串 %s 未结束", src[i:])
+			}
+			body := src[i+1 : j]
+			if r == '\'' {
+				body = strings.Replace(body, `"`, `\"`, -1)
+			}
+			s, e := strconv.Unquote(`"` + body + `"`)
+			if e != nil {
+				return nil, fmt.Errorf("字符串 %s 有误", src[i:j+1])
+			}
+			tokens = append(tokens, exprToken{kind: tokenString, text: src[i : j+1], val: s})
+			i = j + 1
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(src) {
+				r, size := utf8.DecodeRuneInString(src[j:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				j += size
+			}
+			tokens = append(tokens, exprToken{kind: tokenIdent, text: src[i:j]})
+			i = j
+		default:
+			op := ""
+			for _, o := range exprOps {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("无法识别的字符 %q", r)
+			}
+			tokens = append(tokens, exprToken{kind: tokenOp, text: op})
+			i += len(op)
+		}
+	}
+	return
+}
+
+// 语法，优先级 || < && < 比较 < + - < * / % < 一元运算
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	typ    reflect.Type
+}
+
+func (p *exprParser) peekOp(ops ...string) string {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenOp {
+		return ""
+	}
+	for _, op := range ops {
+		if p.tokens[p.pos].text == op {
+			return op
+		}
+	}
+	return ""
+}
+
+func (p *exprParser) expectOp(op string) error {
+	if p.peekOp(op) == "" {
+		return fmt.Errorf("缺少 %q", op)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *exprParser) parseBinary(next func() (exprNode, error), ops ...string) (exprNode, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peekOp(ops...)
+		if op == "" {
+			return left, nil
+		}
+		p.pos++
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	return p.parseBinary(p.parseCompare, "&&")
+}
+
+func (p *exprParser) parseCompare() (exprNode, error) {
+	return p.parseBinary(p.parseAdd, "==", "!=", "<=", ">=", "<", ">")
+}
+
+func (p *exprParser) parseAdd() (exprNode, error) {
+	return p.parseBinary(p.parseMul, "+", "-")
+}
+
+func (p *exprParser) parseMul() (exprNode, error) {
+	return p.parseBinary(p.parseUnary, "*", "/", "%")
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if op := p.peekOp("!", "-"); op != "" {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprUnary{op: op, x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("表达式不完整")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok.kind {
+	case tokenNumber, tokenString:
+		return &exprLiteral{val: tok.val}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &exprLiteral{val: true}, nil
+		case "false":
+			return &exprLiteral{val: false}, nil
+		case "nil":
+			return &exprLiteral{val: nil}, nil
+		case "len":
+			if p.peekOp("(") != "" {
+				return p.parseLen()
+			}
+		}
+		return p.parseField(tok.text)
+	}
+	if tok.text == "(" {
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expectOp(")")
+	}
+	return nil, fmt.Errorf("多余的 %q", tok.text)
+}
+
+func (p *exprParser) parseLen() (exprNode, error) {
+	p.pos++
+	x, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return &exprLen{x: x}, p.expectOp(")")
+}
+
+// parseField 解析字段及嵌套字段，编译时按结构体类型取字段下标
+func (p *exprParser) parseField(name string) (exprNode, error) {
+	field := &exprField{}
+	typ := p.typ
+	for {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%s 不是结构体", name)
+		}
+		sf, ok := typ.FieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("字段 %s 不存在", name)
+		}
+		field.path = append(field.path, sf.Index)
+		field.name = append(field.name, name)
+		typ = sf.Type
+		if p.peekOp(".") == "" {
+			return field, nil
+		}
+		p.pos++
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenIdent {
+			return nil, fmt.Errorf("%s. 之后缺少字段名", strings.Join(field.name, "."))
+		}
+		name = p.tokens[p.pos].text
+		p.pos++
+	}
+}
+
+// 求值
+
+type exprLiteral struct {
+	val interface{}
+}
+
+func (e *exprLiteral) eval(sv reflect.Value) (interface{}, error) {
+	return e.val, nil
+}
+
+type exprField struct {
+	path [][]int
+	name []string
+}
+
+// eval 逐级取字段，路径上(含嵌入字段)的 nil 指针按 nil 处理
+func (e *exprField) eval(sv reflect.Value) (interface{}, error) {
+	fv := sv
+	for _, index := range e.path {
+		for _, i := range index {
+			for fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					return nil, nil
+				}
+				fv = fv.Elem()
+			}
+			fv = fv.Field(i)
+		}
+	}
+	return exprValue(fv), nil
+}
+
+// exprValue 字段值转为表达式的值，数字转为 float64，nil 指针为 nil，其余类型保留 reflect.Value
+func exprValue(fv reflect.Value) interface{} {
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(fv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(fv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return fv.Float()
+	case reflect.String:
+		return fv.String()
+	case reflect.Bool:
+		return fv.Bool()
+	case reflect.Slice, reflect.Map:
+		if fv.IsNil() {
+			return nil
+		}
+	}
+	return fv
+}
+
+type exprLen struct {
+	x exprNode
+}
+
+func (e *exprLen) eval(sv reflect.Value) (interface{}, error) {
+	x, err := e.x.eval(sv)
+	if err != nil {
+		return nil, err
+	}
+	switch val := x.(type) {
+	case nil:
+		return float64(0), nil
+	case string:
+		return float64(utf8.RuneCountInString(val)), nil
+	case reflect.Value:
+		switch val.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+			return float64(val.Len()), nil
+		}
+	}
+	return nil, fmt.Errorf("len 不支持 %v", x)
+}
+
+type exprUnary struct {
+	op string
+	x  exprNode
+}
+
+func (e *exprUnary) eval(sv reflect.Value) (interface{}, error) {
+	x, err := e.x.eval(sv)
+	if err != nil {
+		return nil, err
+	}
+	switch val := x.(type) {
+	case bool:
+		if e.op == "!" {
+			return !val, nil
+		}
+	case float64:
+		if e.op == "-" {
+			return -val, nil
+		}
+	}
+	return nil, fmt.Errorf("%s 不支持 %v", e.op, x)
+}
+
+type exprBinary struct {
+	op    string
+	left  exprNode
+	right exprNode
+}
+
+func (e *exprBinary) eval(sv reflect.Value) (interface{}, error) {
+	x, err := e.left.eval(sv)
+	if err != nil {
+		return nil, err
+	}
+	// && 和 || 短路求值
+	if e.op == "&&" || e.op == "||" {
+		b, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s 的操作数不是 bool: %v", e.op, x)
+		}
+		if b == (e.op == "||") {
+			return b, nil
+		}
+		y, err := e.right.eval(sv)
+		if err != nil {
+			return nil, err
+		}
+		if b, ok = y.(bool); !ok {
+			return nil, fmt.Errorf("%s 的操作数不是 bool: %v", e.op, y)
+		}
+		return b, nil
+	}
+	y, err := e.right.eval(sv)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "==":
+		return exprEqual(x, y), nil
+	case "!=":
+		return !exprEqual(x, y), nil
+	}
+	switch a := x.(type) {
+	case float64:
+		if b, ok := y.(float64); ok {
+			return exprNumber(e.op, a, b)
+		}
+	case string:
+		if b, ok := y.(string); ok {
+			return exprString(e.op, a, b)
+		}
+	}
+	return nil, fmt.Errorf("%v %s %v 类型不匹配", x, e.op, y)
+}
+
+func exprEqual(x, y interface{}) bool {
+	if rv, ok := x.(reflect.Value); ok {
+		x = exprInterface(rv)
+	}
+	if rv, ok := y.(reflect.Value); ok {
+		y = exprInterface(rv)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// exprInterface 不可导出的字段无法取值，按字符串比较
+func exprInterface(rv reflect.Value) interface{} {
+	if rv.CanInterface() {
+		return rv.Interface()
+	}
+	return fmt.Sprintf("%v", rv)
+}
+
+func exprNumber(op string, a, b float64) (interface{}, error) {
+	switch op {
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	case ">":
+		return a > b, nil
+	case ">=":
+		return a >= b, nil
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, fmt.Errorf("除数为 0")
+		}
+		return a / b, nil
+	case "%":
+		if int64(b) == 0 {
+			return nil, fmt.Errorf("除数为 0")
+		}
+		return float64(int64(a) % int64(b)), nil
+	}
+	return nil, fmt.Errorf("数字不支持 %s", op)
+}
+
+func exprString(op string, a, b string) (interface{}, error) {
+	switch op {
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	case ">":
+		return a > b, nil
+	case ">=":
+		return a >= b, nil
+	case "+":
+		return a + b, nil
+	}
+	return nil, fmt.Errorf("字符串不支持 %s", op)
+}
@@ -0,0 +1,118 @@
+package validators
+
+import "unicode"
+
+// 字素簇(grapheme cluster)切分，按 Unicode UAX #29 的扩展字素簇规则简化实现
+
+type graphemeProp uint8
+
+const (
+	gpOther graphemeProp = iota
+	gpCR
+	gpLF
+	gpControl
+	gpExtend
+	gpZWJ
+	gpRegionalIndicator
+	gpSpacingMark
+	gpL
+	gpV
+	gpT
+	gpLV
+	gpLVT
+	gpExtPict
+)
+
+// graphemeCount 字素簇个数
+func graphemeCount(s string) int {
+	count := 0
+	prev := gpControl
+	riCount := 0
+	for i, r := range s {
+		prop := graphemePropOf(r)
+		if i == 0 || graphemeBreak(prev, prop, riCount) {
+			count++
+		}
+		if prop == gpRegionalIndicator {
+			riCount++
+		} else {
+			riCount = 0
+		}
+		prev = prop
+	}
+	return count
+}
+
+// graphemeBreak 判断 prev 与 next 之间是否为字素簇边界，riCount 为 next 之前连续的区域指示符个数
+func graphemeBreak(prev, next graphemeProp, riCount int) bool {
+	switch {
+	case prev == gpCR && next == gpLF: // GB3
+		return false
+	case prev == gpCR || prev == gpLF || prev == gpControl: // GB4
+		return true
+	case next == gpCR || next == gpLF || next == gpControl: // GB5
+		return true
+	case prev == gpL && (next == gpL || next == gpV || next == gpLV || next == gpLVT): // GB6
+		return false
+	case (prev == gpLV || prev == gpV) && (next == gpV || next == gpT): // GB7
+		return false
+	case (prev == gpLVT || prev == gpT) && next == gpT: // GB8
+		return false
+	case next == gpExtend || next == gpZWJ || next == gpSpacingMark: // GB9, GB9a
+		return false
+	case prev == gpZWJ && next == gpExtPict: // GB11
+		return false
+	case prev == gpRegionalIndicator && next == gpRegionalIndicator: // GB12, GB13
+		return riCount%2 == 0
+	}
+	return true // GB999
+}
+
+func graphemePropOf(r rune) graphemeProp {
+	switch {
+	case r == '\r':
+		return gpCR
+	case r == '\n':
+		return gpLF
+	case r == 0x200D:
+		return gpZWJ
+	case r == 0x200C, r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F, r == 0xFF9E, r == 0xFF9F:
+		return gpExtend
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gpRegionalIndicator
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return gpExtend
+	case unicode.Is(unicode.Mc, r):
+		return gpSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gpControl
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gpL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gpV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gpT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gpLV
+		}
+		return gpLVT
+	case isExtendedPictographic(r):
+		return gpExtPict
+	}
+	return gpOther
+}
+
+// isExtendedPictographic emoji 等图形符号的近似范围
+func isExtendedPictographic(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF,
+		r >= 0x2600 && r <= 0x27BF,
+		r >= 0x2300 && r <= 0x23FF,
+		r >= 0x2190 && r <= 0x21FF,
+		r >= 0x2B00 && r <= 0x2BFF:
+		return true
+	}
+	return r == 0xA9 || r == 0xAE || r == 0x203C || r == 0x2049 || r == 0x2122 ||
+		r == 0x2139 || r == 0x3030 || r == 0x303D || r == 0x3297 || r == 0x3299
+}
@@ -1,11 +1,106 @@
 package validators
 
 const (
-	ValidNotExist = "ValidNotExist"
-	ValidError    = "ValidError"
-	ValidIsNumber = "ValidIsNumber"
-	ValidIsPhone  = "ValidIsPhone"
-	ValidIsUrl    = "ValidIsUrl"
+	ValidNotExist         = "ValidNotExist"
+	ValidError            = "ValidError"
+	ValidIsNumber         = "ValidIsNumber"
+	ValidIsPhone          = "ValidIsPhone"
+	ValidIsUrl            = "ValidIsUrl"
+	ValidIsUSCC           = "ValidIsUSCC"
+	ValidIsOrgCode        = "ValidIsOrgCode"
+	ValidIsTaxNo          = "ValidIsTaxNo"
+	ValidPhoneRegion      = "ValidPhoneRegion"
+	ValidIsE164           = "ValidIsE164"
+	ValidIsLandline       = "ValidIsLandline"
+	ValidIsLuhn           = "ValidIsLuhn"
+	ValidIsBankCard       = "ValidIsBankCard"
+	ValidIsCreditCard     = "ValidIsCreditCard"
+	ValidCardBrand        = "ValidCardBrand"
+	ValidIsIBAN           = "ValidIsIBAN"
+	ValidIsPassword       = "ValidIsPassword"
+	ValidPasswordPolicy   = "ValidPasswordPolicy"
+	ValidPasswordMin      = "ValidPasswordMin"
+	ValidPasswordMax      = "ValidPasswordMax"
+	ValidPasswordUpper    = "ValidPasswordUpper"
+	ValidPasswordLower    = "ValidPasswordLower"
+	ValidPasswordDigit    = "ValidPasswordDigit"
+	ValidPasswordSpecial  = "ValidPasswordSpecial"
+	ValidPasswordNoRepeat = "ValidPasswordNoRepeat"
+	ValidPasswordEntropy  = "ValidPasswordEntropy"
+	ValidPasswordBanned   = "ValidPasswordBanned"
+	ValidIsRegex          = "ValidIsRegex"
+	ValidPatternNotExist  = "ValidPatternNotExist"
+	ValidPatternError     = "ValidPatternError"
+	ValidContains         = "ValidContains"
+	ValidContainsAny      = "ValidContainsAny"
+	ValidExcludes         = "ValidExcludes"
+	ValidExcludesAll      = "ValidExcludesAll"
+	ValidStartsWith       = "ValidStartsWith"
+	ValidEndsWith         = "ValidEndsWith"
+	ValidLowercase        = "ValidLowercase"
+	ValidUppercase        = "ValidUppercase"
+	ValidNotBlank         = "ValidNotBlank"
+	ValidTimeLt           = "ValidTimeLt"
+	ValidTimeLte          = "ValidTimeLte"
+	ValidTimeGt           = "ValidTimeGt"
+	ValidTimeGte          = "ValidTimeGte"
+	ValidTimeType         = "ValidTimeType"
+	ValidTimeParam        = "ValidTimeParam"
+	ValidIsDuration       = "ValidIsDuration"
+	ValidDurationRange    = "ValidDurationRange"
+	ValidIsDate           = "ValidIsDate"
+	ValidWeekday          = "ValidWeekday"
+	ValidWeekdayName      = "ValidWeekdayName"
+	ValidIsTimeOfDay      = "ValidIsTimeOfDay"
+	ValidTimeOfDay        = "ValidTimeOfDay"
+	ValidTimezone         = "ValidTimezone"
+	ValidIsDecimal        = "ValidIsDecimal"
+	ValidDecimalParam     = "ValidDecimalParam"
+	ValidDecimalEq        = "ValidDecimalEq"
+	ValidDecimalLt        = "ValidDecimalLt"
+	ValidDecimalLte       = "ValidDecimalLte"
+	ValidDecimalGt        = "ValidDecimalGt"
+	ValidDecimalGte       = "ValidDecimalGte"
+	ValidDecimalDigits    = "ValidDecimalDigits"
+	ValidBetween          = "ValidBetween"
+	ValidMultipleOf       = "ValidMultipleOf"
+	ValidIn               = "ValidIn"
+	ValidEnum             = "ValidEnum"
+	ValidEnumNotExist     = "ValidEnumNotExist"
+	ValidEnumUnexported   = "ValidEnumUnexported"
+	ValidEnumMethod       = "ValidEnumMethod"
+	ValidUnique           = "ValidUnique"
+	ValidUniqueType       = "ValidUniqueType"
+	ValidAnyOf            = "ValidAnyOf"
+	ValidNot              = "ValidNot"
+	ValidRuleSyntax       = "ValidRuleSyntax"
+	ValidAlias            = "ValidAlias"
+	ValidAliasCycle       = "ValidAliasCycle"
+	ValidExpr             = "ValidExpr"
+	ValidExprSyntax       = "ValidExprSyntax"
+	ValidExprEval         = "ValidExprEval"
+	ValidExprStruct       = "ValidExprStruct"
+	ValidRuleMode         = "ValidRuleMode"
+	ValidRuleType         = "ValidRuleType"
+	ValidRuleField        = "ValidRuleField"
+	ValidRequired         = "ValidRequired"
+	ValidIsEmail          = "ValidIsEmail"
+	ValidIsMobile         = "ValidIsMobile"
+	ValidIsUUID           = "ValidIsUUID"
+	ValidLength           = "ValidLength"
+	ValidLengthEq         = "ValidLengthEq"
+	ValidLengthLt         = "ValidLengthLt"
+	ValidLengthLte        = "ValidLengthLte"
+	ValidLengthGt         = "ValidLengthGt"
+	ValidLengthGte        = "ValidLengthGte"
+	ValidJSONType         = "ValidJSONType"
+	ValidJSONTrailing     = "ValidJSONTrailing"
+	ValidValuer           = "ValidValuer"
+	ValidPresent          = "ValidPresent"
+	ValidSchemaKeyword    = "ValidSchemaKeyword"
+	ValidRuleParams       = "ValidRuleParams"
+	ValidRuleParam        = "ValidRuleParam"
+	ValidRuleKind         = "ValidRuleKind"
 )
 
 var Lang map[string]map[string]string
@@ -0,0 +1,401 @@
+package validators
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// 由校验规则导出 JSON Schema(draft 2020-12)，文档与校验规则保持一致
+
+// JSONSchemaDraft 导出的 JSON Schema 版本
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema JSON Schema 文档，只包含校验规则能够表达的关键字
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MultipleOf           *float64           `json:"multipleOf,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	ErrorMessages        map[string]string  `json:"x-error-messages,omitempty"` // OpenAPI 扩展，规则名到错误信息
+
+	types []string // 解析时 type 为数组的值
+}
+
+// schemaBuilder 按校验规则生成 schema，openAPI 为 true 时写入 x-error-messages，并跳过作为参数的字段
+type schemaBuilder struct {
+	v        *Validator
+	set      *ruleSet
+	visiting map[reflect.Type]bool // 正在展开的结构体，避免递归类型无限展开
+	openAPI  bool
+}
+
+func (v *Validator) newSchemaBuilder(openAPI bool) *schemaBuilder {
+	return &schemaBuilder{v: v, set: v.loadedRules(), visiting: make(map[reflect.Type]bool), openAPI: openAPI}
+}
+
+// formatRules 对应 JSON Schema format 的校验规则
+var formatRules = map[string]string{
+	"email": "email",
+	"uuid":  "uuid",
+	"ipv4":  "ipv4",
+	"ipv6":  "ipv6",
+	"date":  "date",
+}
+
+// JSONSchema 按结构体的校验规则导出 JSON Schema，属性名取 json tag，标题取 TitleTag
+// 规则中的 ";" 组合及别名会展开，"|" 和 "!" 组合的规则无法表达，导出时忽略
+func (v *Validator) JSONSchema(s interface{}) (*Schema, error) {
+	rt := reflect.TypeOf(s)
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	schema, err := v.newSchemaBuilder(false).typeSchema(rt, nil)
+	if err != nil {
+		return nil, err
+	}
+	schema.Schema = JSONSchemaDraft
+	if schema.Title == "" {
+		schema.Title = rt.Name()
+	}
+	return schema, nil
+}
+
+// MarshalJSONSchema 导出 JSON 格式的 JSON Schema
+func (v *Validator) MarshalJSONSchema(s interface{}) ([]byte, error) {
+	schema, err := v.JSONSchema(s)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// typeSchema 类型对应的 schema，scope 为外层结构体传入的配置规则
+func (b *schemaBuilder) typeSchema(rt reflect.Type, scope *ruleScope) (*Schema, error) {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	schema := &Schema{}
+	// 自定义类型按转换后的类型导出，如 sql.NullString 为 string，无法确定时不限类型
+	if ct, ok := b.v.customType(rt); ok {
+		if ct == nil || ct == rt {
+			return schema, nil
+		}
+		return b.typeSchema(ct, scope)
+	}
+	switch {
+	case rt == timeType:
+		schema.Type, schema.Format = "string", "date-time"
+		return schema, nil
+	case rt == durationType:
+		schema.Type = "integer"
+		return schema, nil
+	case isBigType(rt):
+		schema.Type = "number"
+		return schema, nil
+	}
+	switch rt.Kind() {
+	case reflect.String:
+		schema.Type = "string"
+	case reflect.Bool:
+		schema.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		schema.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema.Type = "number"
+	case reflect.Slice, reflect.Array:
+		if rt.Elem().Kind() == reflect.Uint8 {
+			schema.Type = "string"
+			break
+		}
+		schema.Type = "array"
+		items, err := b.typeSchema(rt.Elem(), scope)
+		if err != nil {
+			return nil, err
+		}
+		schema.Items = items
+	case reflect.Map:
+		schema.Type = "object"
+		values, err := b.typeSchema(rt.Elem(), scope)
+		if err != nil {
+			return nil, err
+		}
+		schema.AdditionalProperties = values
+	case reflect.Struct:
+		schema.Type = "object"
+		if b.visiting[rt] {
+			return schema, nil
+		}
+		b.visiting[rt] = true
+		defer delete(b.visiting, rt)
+		schema.Properties = make(map[string]*Schema)
+		if err := b.structSchema(rt, schema, b.set.scope(rt, scope)); err != nil {
+			return nil, err
+		}
+	}
+	return schema, nil
+}
+
+// structSchema 导出结构体字段，匿名嵌入且没有 json 名称的结构体字段展开到上一层
+func (b *schemaBuilder) structSchema(rt reflect.Type, schema *Schema, scope *ruleScope) error {
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		name, skip := jsonFieldName(sf)
+		if skip {
+			continue
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if err := b.structSchema(ft, schema, b.set.scope(ft, scope.child(sf.Name))); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, in := parameterName(sf); b.openAPI && in != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		prop, required, err := b.fieldSchema(rt, sf, scope)
+		if err != nil {
+			return err
+		}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = prop
+	}
+	return nil
+}
+
+// fieldSchema 字段的 schema，required 表示字段必填
+func (b *schemaBuilder) fieldSchema(rt reflect.Type, sf reflect.StructField, scope *ruleScope) (prop *Schema, required bool, err error) {
+	if prop, err = b.typeSchema(sf.Type, scope.child(sf.Name)); err != nil {
+		return
+	}
+	prop.Title = sf.Tag.Get(b.v.TitleTag)
+	tag := b.v.fieldRules(scope, rt, sf)
+	if tag == "" {
+		return
+	}
+	node, err := b.v.compileRule(tag)
+	if err != nil {
+		return
+	}
+	title := prop.Title
+	if title == "" {
+		title = sf.Name
+	}
+	required = b.applyRules(node, prop, title)
+	return
+}
+
+// jsonFieldName 字段的 json 名称，skip 表示不导出
+func jsonFieldName(sf reflect.StructField) (name string, skip bool) {
+	if sf.PkgPath != "" && !sf.Anonymous {
+		return "", true
+	}
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	if num := strings.Index(tag, ","); num != -1 {
+		tag = tag[:num]
+	}
+	return tag, false
+}
+
+// applyRules 将规则写入字段的 schema，返回字段是否必填
+func (b *schemaBuilder) applyRules(node *ruleNode, prop *Schema, title string) (required bool) {
+	switch node.kind {
+	case ruleAnd, ruleAlias:
+		for _, child := range node.children {
+			if b.applyRules(child, prop, title) {
+				required = true
+			}
+		}
+		return
+	case ruleLeaf:
+		if b.openAPI {
+			if msg, ok := ruleMessage(node.name, title, node.params, prop); ok {
+				if prop.ErrorMessages == nil {
+					prop.ErrorMessages = make(map[string]string)
+				}
+				prop.ErrorMessages[node.name] = msg
+			}
+		}
+		return b.v.applySchemaRule(node.name, node.params, prop)
+	}
+	return
+}
+
+func (v *Validator) applySchemaRule(name string, params []string, prop *Schema) (required bool) {
+	// 数组的 in、format 等规则作用于元素
+	target := prop
+	if prop.Type == "array" && prop.Items != nil {
+		target = prop.Items
+	}
+	switch name {
+	case "required":
+		return true
+	case "len", "len_runes":
+		if len(params) == 1 {
+			params = append(params, params[0])
+		}
+		if len(params) == 2 {
+			setSchemaBound(prop, opGte, params[0])
+			setSchemaBound(prop, opLte, params[1])
+		}
+	case "min", "gte":
+		setSchemaBound(prop, opGte, schemaParam(params))
+	case "max", "lte":
+		setSchemaBound(prop, opLte, schemaParam(params))
+	case "gt":
+		setSchemaBound(prop, opGt, schemaParam(params))
+	case "lt":
+		setSchemaBound(prop, opLt, schemaParam(params))
+	case "eq":
+		if prop.Type == "string" {
+			prop.Const = schemaParam(params)
+		} else if p := schemaNumber(schemaParam(params)); p != nil && (prop.Type == "integer" || prop.Type == "number") {
+			prop.Const = *p
+		}
+	case "between":
+		if len(params) == 2 && prop.Type != "string" {
+			prop.Minimum, prop.Maximum = schemaNumber(params[0]), schemaNumber(params[1])
+		}
+	case "multiple_of":
+		prop.MultipleOf = schemaNumber(schemaParam(params))
+	case "unique", "unique_i":
+		if prop.Type == "array" {
+			prop.UniqueItems = true
+		}
+	case "in":
+		for _, param := range v.expandEnum(params) {
+			target.Enum = append(target.Enum, schemaEnumValue(target.Type, param))
+		}
+	case "regex":
+		pattern := strings.Join(params, VALIDATOR_RANGE_SPLIT)
+		if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			target.Pattern = pattern[1 : len(pattern)-1]
+		} else if re, ok := v.pattern(pattern); ok {
+			target.Pattern = re.String()
+		}
+	case "date":
+		if len(params) == 0 {
+			target.Format = formatRules[name]
+		}
+	default:
+		if format, ok := formatRules[name]; ok {
+			target.Format = format
+		}
+	}
+	return
+}
+
+// setSchemaBound 按类型写入范围，字符串为长度，数组为元素个数，数字为取值
+func setSchemaBound(prop *Schema, op string, param string) {
+	if param == "" || param == VALIDATOR_IGNORE_SIGN {
+		return
+	}
+	switch prop.Type {
+	case "string", "array":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		switch op {
+		case opGt:
+			n, op = n+1, opGte
+		case opLt:
+			n, op = n-1, opLte
+		}
+		min, max := &prop.MinLength, &prop.MaxLength
+		if prop.Type == "array" {
+			min, max = &prop.MinItems, &prop.MaxItems
+		}
+		if op == opGte {
+			*min = &n
+		} else {
+			*max = &n
+		}
+	case "integer", "number":
+		p := schemaNumber(param)
+		switch op {
+		case opGte:
+			prop.Minimum = p
+		case opLte:
+			prop.Maximum = p
+		case opGt:
+			prop.ExclusiveMinimum = p
+		case opLt:
+			prop.ExclusiveMaximum = p
+		}
+	}
+}
+
+// expandEnum 展开 in 参数中的命名枚举
+func (v *Validator) expandEnum(params []string) (values []string) {
+	for _, param := range params {
+		if strings.HasPrefix(param, VALIDATOR_ENUM_SIGN) {
+			enum, _ := v.enum(strings.TrimPrefix(param, VALIDATOR_ENUM_SIGN))
+			values = append(values, enum...)
+			continue
+		}
+		values = append(values, param)
+	}
+	return
+}
+
+func schemaParam(params []string) string {
+	if len(params) != 1 {
+		return ""
+	}
+	return params[0]
+}
+
+func schemaNumber(param string) *float64 {
+	f, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+// schemaEnumValue 枚举值按字段类型转为 JSON 值
+func schemaEnumValue(typ string, param string) interface{} {
+	switch typ {
+	case "integer", "number":
+		if f := schemaNumber(param); f != nil {
+			return *f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(param); err == nil {
+			return b
+		}
+	}
+	return param
+}
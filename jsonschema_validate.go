@@ -0,0 +1,329 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 将 JSON Schema 编译为按路径的校验规则，用于校验原始 JSON
+//
+// 支持 type(字符串或数组)、required、properties、items、enum、const、minLength、maxLength、
+// minimum、maximum、exclusiveMinimum、exclusiveMaximum、multipleOf、
+// minItems、maxItems、uniqueItems、pattern 及 schemaFormats 中的 format，其余关键字忽略
+// 与 JSON Schema 一致，未声明 type 时类型相关的关键字只作用于对应类型的值；值为 null 视为缺少
+
+// schemaFormats JSON Schema format 对应的校验规则
+var schemaFormats = map[string]string{
+	"email":     "email",
+	"uuid":      "uuid",
+	"ipv4":      "ipv4",
+	"ipv6":      "ipv6",
+	"date":      "date",
+	"date-time": "date=" + escapeRuleParam("2006-01-02T15:04:05Z07:00"),
+}
+
+// CompileJSONSchema 将 JSON Schema 编译为路径到规则的映射，可用于 ValidateJSON 和 ValidateMap
+func (v *Validator) CompileJSONSchema(data []byte) (map[string]string, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	list := make(map[string][]string)
+	if err := compileSchema(&schema, "", list); err != nil {
+		return nil, err
+	}
+	rules := make(map[string]string, len(list))
+	for path, rule := range list {
+		if len(rule) > 0 {
+			rules[path] = strings.Join(rule, VALIDATOR_MUTIPLE_SPLIT)
+		}
+	}
+	// 生成的规则有误(如 pattern 无法编译)时直接返回，不等到校验时才出错
+	paths := make([]string, 0, len(rules))
+	for path := range rules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if errs := v.CheckTag(rules[path], TypeAny); len(errs) > 0 {
+			if te, ok := errs[0].(*TagError); ok {
+				te.Path = path
+			}
+			return nil, errs[0]
+		}
+	}
+	return rules, nil
+}
+
+// UnmarshalJSON 解析 schema，type 可以是字符串或字符串数组
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type plain Schema
+	raw := struct {
+		*plain
+		Type interface{} `json:"type"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch typ := raw.Type.(type) {
+	case nil:
+	case string:
+		s.Type = typ
+	case []interface{}:
+		for _, item := range typ {
+			name, ok := item.(string)
+			if !ok {
+				return fmt.Errorf(trans(ValidSchemaKeyword), "type", raw.Type)
+			}
+			s.types = append(s.types, name)
+		}
+	default:
+		return fmt.Errorf(trans(ValidSchemaKeyword), "type", raw.Type)
+	}
+	return nil
+}
+
+// ValidateJSON 按路径规则校验 JSON 文档，根路径为 ""
+// 数字按 json.Number 解析，大整数及小数的比较不损失精度
+func (v *Validator) ValidateJSON(doc []byte, rules map[string]string) (errs []error) {
+	var data interface{}
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return []error{err}
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return []error{fmt.Errorf(trans(ValidJSONTrailing))}
+	}
+	return v.validatePaths(reflect.ValueOf(data), rules)
+}
+
+func compileSchema(s *Schema, path string, list map[string][]string) error {
+	types, err := schemaTypes(s)
+	if err != nil {
+		return err
+	}
+	add := func(typ string, rule string) {
+		// 未声明 type 或声明了多个 type 时只校验对应类型的值
+		if typ != "" && !(len(types) == 1 && (types[0] == typ || (typ == "number" && types[0] == "integer"))) {
+			rule = VALIDATOR_NOT_SIGN + "jsontype=" + typ + VALIDATOR_OR_SIGN + rule
+		}
+		list[path] = append(list[path], rule)
+	}
+	if len(types) > 0 {
+		add("", "jsontype="+strings.Join(types, VALIDATOR_RANGE_SPLIT))
+	}
+
+	if s.MinLength != nil || s.MaxLength != nil {
+		add("string", "len_runes="+schemaIntBound(s.MinLength)+VALIDATOR_RANGE_SPLIT+schemaIntBound(s.MaxLength))
+	}
+	if s.Pattern != "" {
+		add("string", schemaPatternRule(s.Pattern))
+	}
+	if rule, ok := schemaFormats[s.Format]; ok {
+		add("string", rule)
+	}
+
+	numberRules := []struct {
+		rule  string
+		value *float64
+	}{
+		{"gte", s.Minimum},
+		{"lte", s.Maximum},
+		{"gt", s.ExclusiveMinimum},
+		{"lt", s.ExclusiveMaximum},
+		{"multiple_of", s.MultipleOf},
+	}
+	for _, r := range numberRules {
+		if r.value != nil {
+			add("number", r.rule+VALIDATOR_VALUE_SIGN+strconv.FormatFloat(*r.value, 'f', -1, 64))
+		}
+	}
+
+	if s.MinItems != nil || s.MaxItems != nil {
+		add("array", "len="+schemaIntBound(s.MinItems)+VALIDATOR_RANGE_SPLIT+schemaIntBound(s.MaxItems))
+	}
+	if s.UniqueItems {
+		add("array", "unique")
+	}
+
+	enum := s.Enum
+	if s.Const != nil {
+		enum = append(enum, s.Const)
+	}
+	if len(enum) > 0 {
+		params := make([]string, 0, len(enum))
+		for _, val := range enum {
+			switch val.(type) {
+			case string, float64, bool, json.Number:
+				literal, _ := json.Marshal(val)
+				params = append(params, escapeRuleParam(string(literal)))
+			default:
+				return fmt.Errorf(trans(ValidSchemaKeyword), "enum", val)
+			}
+		}
+		add("", "jsonenum="+strings.Join(params, VALIDATOR_RANGE_SPLIT))
+	}
+
+	for _, name := range s.Required {
+		child := joinPath(path, name)
+		list[child] = append([]string{"present"}, list[child]...)
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := compileSchema(s.Properties[name], joinPath(path, name), list); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return compileSchema(s.Items, joinPath(path, VALIDATOR_ANY_SIGN), list)
+	}
+	return nil
+}
+
+// schemaTypes schema 声明的类型，null 视为缺少，不参与类型校验
+func schemaTypes(s *Schema) (types []string, err error) {
+	declared := s.types
+	if len(declared) == 0 && s.Type != "" {
+		declared = []string{s.Type}
+	}
+	for _, typ := range declared {
+		switch typ {
+		case "null":
+		case "string", "number", "integer", "boolean", "array", "object":
+			types = append(types, typ)
+		default:
+			return nil, fmt.Errorf(trans(ValidSchemaKeyword), "type", typ)
+		}
+	}
+	return
+}
+
+func schemaIntBound(n *int) string {
+	if n == nil {
+		return VALIDATOR_IGNORE_SIGN
+	}
+	return strconv.Itoa(*n)
+}
+
+// escapeRuleParam 转义规则参数中的分隔符
+// schemaPatternRule pattern 对应的内联正则规则，未转义的 "/" 转为 "\/"，";" 转为 "\;"
+func schemaPatternRule(pattern string) string {
+	var buf strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			buf.WriteByte(c)
+			i++
+			buf.WriteByte(pattern[i])
+		case c == '/':
+			buf.WriteString(VALIDATOR_ESCAPE_SIGN + "/")
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return "regex=/" + strings.Replace(buf.String(), VALIDATOR_MUTIPLE_SPLIT, VALIDATOR_ESCAPE_SIGN+VALIDATOR_MUTIPLE_SPLIT, -1) + "/"
+}
+
+func escapeRuleParam(param string) string {
+	for _, sign := range []string{VALIDATOR_MUTIPLE_SPLIT, VALIDATOR_RANGE_SPLIT, VALIDATOR_OR_SIGN, VALIDATOR_GROUP_OPEN, VALIDATOR_GROUP_END} {
+		param = strings.Replace(param, sign, VALIDATOR_ESCAPE_SIGN+sign, -1)
+	}
+	return param
+}
+
+// isJSONType 值的 JSON 类型，jsontype=string,number，integer 为没有小数部分的数字
+func isJSONType(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	typ := jsonType(fv)
+	for _, param := range params {
+		if param == typ || (param == "number" && typ == "integer") {
+			return
+		}
+	}
+	return fmt.Errorf(trans(ValidJSONType), title, params)
+}
+
+// isJSONEnum JSON 值等于任一参数，参数为 JSON 字面量，如 jsonenum="new",1,true
+// 数字按数值比较(1 与 1.0 相等)，字符串和布尔值只与同类型的参数比较
+func isJSONEnum(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	typ := jsonType(fv)
+	for _, param := range params {
+		dec := json.NewDecoder(strings.NewReader(param))
+		dec.UseNumber()
+		var want interface{}
+		if dec.Decode(&want) != nil {
+			continue
+		}
+		switch want := want.(type) {
+		case string:
+			if typ == "string" && fv.String() == want {
+				return
+			}
+		case bool:
+			if typ == "boolean" && fv.Bool() == want {
+				return
+			}
+		case json.Number:
+			if typ != "integer" && typ != "number" {
+				continue
+			}
+			val, ok := decimalValue(fv)
+			if r, valid := new(big.Rat).SetString(want.String()); ok && valid && val.Cmp(r) == 0 {
+				return
+			}
+		}
+	}
+	return fmt.Errorf(trans(ValidIn), fv, params)
+}
+
+func jsonType(fv reflect.Value) string {
+	if fv.IsValid() && fv.Type() == jsonNumberType {
+		r, ok := new(big.Rat).SetString(fv.String())
+		if !ok {
+			return ""
+		}
+		if r.IsInt() {
+			return "integer"
+		}
+		return "number"
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		if f := fv.Float(); f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return "integer"
+		}
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return ""
+}
+
+// isPresent 值存在，与 required 不同，空字符串和 0 视为存在
+func isPresent(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if !fv.IsValid() {
+		err = fmt.Errorf(trans(ValidPresent), title)
+	}
+	return
+}
@@ -1,9 +1,104 @@
 package validators
 
 var en = map[string]string{
-	ValidNotExist: "Validator %v not exist",
-	ValidError:    "Validator %v error",
-	ValidIsNumber: "Validator %v non-digital",
-	ValidIsPhone:  "Incorrect format of mobile phone number (%v)",
-	ValidIsUrl:    "Url format incorrect",
+	ValidNotExist:         "Validator %v not exist",
+	ValidError:            "Validator %v error",
+	ValidIsNumber:         "Validator %v non-digital",
+	ValidIsPhone:          "Incorrect format of mobile phone number (%v)",
+	ValidIsUrl:            "Url format incorrect",
+	ValidIsUSCC:           "%s is not a valid unified social credit code (%v)",
+	ValidIsOrgCode:        "%s is not a valid organization code (%v)",
+	ValidIsTaxNo:          "%s is not a valid taxpayer identification number (%v)",
+	ValidPhoneRegion:      "Phone region %v is not supported",
+	ValidIsE164:           "%s is not a valid E.164 phone number (%v)",
+	ValidIsLandline:       "%s is not a valid landline number (%v)",
+	ValidIsLuhn:           "%s failed the Luhn check (%v)",
+	ValidIsBankCard:       "%s is not a valid bank card number (%v), failed: %s",
+	ValidIsCreditCard:     "%s is not a valid credit card number (%v), failed: %s",
+	ValidCardBrand:        "Card brand %v is not supported",
+	ValidIsIBAN:           "%s is not a valid IBAN (%v), failed: %s",
+	ValidIsPassword:       "%s is too weak: %s",
+	ValidPasswordPolicy:   "Password policy %v is invalid",
+	ValidPasswordMin:      "at least %d characters",
+	ValidPasswordMax:      "at most %d characters",
+	ValidPasswordUpper:    "at least %d uppercase letters",
+	ValidPasswordLower:    "at least %d lowercase letters",
+	ValidPasswordDigit:    "at least %d digits",
+	ValidPasswordSpecial:  "at least %d special characters",
+	ValidPasswordNoRepeat: "no character repeated %d times in a row",
+	ValidPasswordEntropy:  "at least %d bits of entropy",
+	ValidPasswordBanned:   "must not be a commonly used password",
+	ValidIsRegex:          "%s does not match pattern %v",
+	ValidPatternNotExist:  "Pattern %v not exist",
+	ValidPatternError:     "Pattern %v error: %v",
+	ValidContains:         "%s must contain %v",
+	ValidContainsAny:      "%s must contain one of %v",
+	ValidExcludes:         "%s must not contain %v",
+	ValidExcludesAll:      "%s must not contain any of the characters %v",
+	ValidStartsWith:       "%s must start with one of %v",
+	ValidEndsWith:         "%s must end with one of %v",
+	ValidLowercase:        "%s must be lowercase",
+	ValidUppercase:        "%s must be uppercase",
+	ValidNotBlank:         "%s must not be blank",
+	ValidTimeLt:           "%s must be before %v",
+	ValidTimeLte:          "%s must not be after %v",
+	ValidTimeGt:           "%s must be after %v",
+	ValidTimeGte:          "%s must not be before %v",
+	ValidTimeType:         "%s is not a time field",
+	ValidTimeParam:        "Time param %v is invalid",
+	ValidIsDuration:       "%s is not a valid duration (%v)",
+	ValidDurationRange:    "%s must be between %v and %v",
+	ValidIsDate:           "%s is not a valid date (%v)",
+	ValidWeekday:          "%s must be on %v",
+	ValidWeekdayName:      "Weekday %v not exist",
+	ValidIsTimeOfDay:      "%s is not a valid time of day (%v)",
+	ValidTimeOfDay:        "%s must be between %v and %v",
+	ValidTimezone:         "Timezone %v not exist",
+	ValidIsDecimal:        "%s is not a valid decimal (%v)",
+	ValidDecimalParam:     "Decimal param %v is invalid",
+	ValidDecimalEq:        "%s must equal %v",
+	ValidDecimalLt:        "%s must be less than %v",
+	ValidDecimalLte:       "%s must not be greater than %v",
+	ValidDecimalGt:        "%s must be greater than %v",
+	ValidDecimalGte:       "%s must not be less than %v",
+	ValidDecimalDigits:    "%s allows at most %v integer digits and %v decimal places",
+	ValidBetween:          "%s must be between %v and %v",
+	ValidMultipleOf:       "%s must be a multiple of %v",
+	ValidIn:               "%v is not one of %v",
+	ValidEnum:             "%s value %v is not a valid enum value",
+	ValidEnumNotExist:     "enum %s is not registered",
+	ValidEnumUnexported:   "%s is unexported, enum values can not be read",
+	ValidEnumMethod:       "%s type %v has no IsValid() bool or Values() method, in requires params",
+	ValidUnique:           "%s contains duplicate values at %v",
+	ValidUniqueType:       "%s does not support the unique rule",
+	ValidAnyOf:            "%s must satisfy one of: %s",
+	ValidNot:              "%s must not satisfy %s",
+	ValidRuleSyntax:       "rule %s has a syntax error at %d",
+	ValidAlias:            "%s does not satisfy %s",
+	ValidAliasCycle:       "rule alias %s refers to itself",
+	ValidExpr:             "%s does not satisfy %s",
+	ValidExprSyntax:       "invalid expression %s: %v",
+	ValidExprEval:         "%s cannot evaluate %s: %v",
+	ValidExprStruct:       "expression %s only applies to struct fields",
+	ValidRuleMode:         "invalid rule config mode %s",
+	ValidRuleType:         "struct %s in rule config is not registered",
+	ValidRuleField:        "struct %v has no field %s",
+	ValidRequired:         "%s is required",
+	ValidIsEmail:          "%s is not a valid email",
+	ValidIsMobile:         "%s is not a valid mobile phone number",
+	ValidIsUUID:           "%s is not a valid UUID",
+	ValidLength:           "%s length must be between %v and %v",
+	ValidLengthEq:         "%s length must be %v",
+	ValidLengthLt:         "%s length must be less than %v",
+	ValidLengthLte:        "%s length must not be greater than %v",
+	ValidLengthGt:         "%s length must be greater than %v",
+	ValidLengthGte:        "%s length must not be less than %v",
+	ValidJSONType:         "%s must be of type %v",
+	ValidJSONTrailing:     "unexpected data after the JSON document",
+	ValidValuer:           "%s value failed: %v",
+	ValidPresent:          "%s is missing",
+	ValidSchemaKeyword:    "unsupported JSON Schema %s: %v",
+	ValidRuleParams:       "rule %s expects %s parameters, got %d",
+	ValidRuleParam:        "rule %s has invalid parameter %s",
+	ValidRuleKind:         "rule %s does not apply to %s fields",
 }
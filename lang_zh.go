@@ -1,9 +1,104 @@
 package validators
 
 var zh = map[string]string{
-	ValidNotExist: "校验规则 %v 不存在",
-	ValidError:    "校验错误",
-	ValidIsNumber: "非数字:%s",
-	ValidIsPhone:  "手机号码(%s)不正确",
-	ValidIsUrl:    "Url格式不正确",
+	ValidNotExist:         "校验规则 %v 不存在",
+	ValidError:            "校验错误",
+	ValidIsNumber:         "非数字:%s",
+	ValidIsPhone:          "手机号码(%s)不正确",
+	ValidIsUrl:            "Url格式不正确",
+	ValidIsUSCC:           "%s不是有效的统一社会信用代码(%v)",
+	ValidIsOrgCode:        "%s不是有效的组织机构代码(%v)",
+	ValidIsTaxNo:          "%s不是有效的纳税人识别号(%v)",
+	ValidPhoneRegion:      "不支持的手机号码地区 %v",
+	ValidIsE164:           "%s不是有效的国际电话号码(%v)",
+	ValidIsLandline:       "%s不是有效的固定电话号码(%v)",
+	ValidIsLuhn:           "%s校验位不正确(%v)",
+	ValidIsBankCard:       "%s不是有效的银行卡号(%v)，未通过: %s",
+	ValidIsCreditCard:     "%s不是有效的信用卡号(%v)，未通过: %s",
+	ValidCardBrand:        "不支持的卡组织 %v",
+	ValidIsIBAN:           "%s不是有效的IBAN(%v)，未通过: %s",
+	ValidIsPassword:       "%s强度不足: %s",
+	ValidPasswordPolicy:   "密码策略 %v 配置错误",
+	ValidPasswordMin:      "至少%d个字符",
+	ValidPasswordMax:      "最多%d个字符",
+	ValidPasswordUpper:    "至少%d个大写字母",
+	ValidPasswordLower:    "至少%d个小写字母",
+	ValidPasswordDigit:    "至少%d个数字",
+	ValidPasswordSpecial:  "至少%d个特殊字符",
+	ValidPasswordNoRepeat: "同一字符不能连续出现%d次",
+	ValidPasswordEntropy:  "熵值至少%d位",
+	ValidPasswordBanned:   "不能使用常见弱密码",
+	ValidIsRegex:          "%s不符合格式 %v",
+	ValidPatternNotExist:  "正则 %v 不存在",
+	ValidPatternError:     "正则 %v 错误: %v",
+	ValidContains:         "%s必须包含%v",
+	ValidContainsAny:      "%s必须包含以下任一内容%v",
+	ValidExcludes:         "%s不能包含%v",
+	ValidExcludesAll:      "%s不能包含字符%v",
+	ValidStartsWith:       "%s必须以以下任一内容开头%v",
+	ValidEndsWith:         "%s必须以以下任一内容结尾%v",
+	ValidLowercase:        "%s必须为小写",
+	ValidUppercase:        "%s必须为大写",
+	ValidNotBlank:         "%s不能为空白",
+	ValidTimeLt:           "%s必须早于%v",
+	ValidTimeLte:          "%s不能晚于%v",
+	ValidTimeGt:           "%s必须晚于%v",
+	ValidTimeGte:          "%s不能早于%v",
+	ValidTimeType:         "%s不是时间类型",
+	ValidTimeParam:        "时间参数 %v 格式错误",
+	ValidIsDuration:       "%s不是有效的时长(%v)",
+	ValidDurationRange:    "%s必须在%v到%v之间",
+	ValidIsDate:           "%s不是有效的日期(%v)",
+	ValidWeekday:          "%s必须是%v",
+	ValidWeekdayName:      "星期 %v 不存在",
+	ValidIsTimeOfDay:      "%s不是有效的时刻(%v)",
+	ValidTimeOfDay:        "%s必须在%v到%v之间",
+	ValidTimezone:         "时区 %v 不存在",
+	ValidIsDecimal:        "%s不是有效的数字(%v)",
+	ValidDecimalParam:     "数字参数 %v 格式错误",
+	ValidDecimalEq:        "%s必须等于%v",
+	ValidDecimalLt:        "%s必须小于%v",
+	ValidDecimalLte:       "%s不能大于%v",
+	ValidDecimalGt:        "%s必须大于%v",
+	ValidDecimalGte:       "%s不能小于%v",
+	ValidDecimalDigits:    "%s最多%v位整数、%v位小数",
+	ValidBetween:          "%s必须在%v到%v之间",
+	ValidMultipleOf:       "%s必须是%v的整数倍",
+	ValidIn:               "%v不在指定范围:%v",
+	ValidEnum:             "%s的值%v不是有效的枚举值",
+	ValidEnumNotExist:     "枚举%s未注册",
+	ValidEnumUnexported:   "%s未导出，无法读取枚举值",
+	ValidEnumMethod:       "%s的类型%v未实现 IsValid() bool 或 Values() 方法，in 需要指定参数",
+	ValidUnique:           "%s存在重复值，重复位置:%v",
+	ValidUniqueType:       "%s的类型不支持唯一值校验",
+	ValidAnyOf:            "%s不满足任一条件: %s",
+	ValidNot:              "%s不能满足%s",
+	ValidRuleSyntax:       "规则%s格式有误，位置%d",
+	ValidAlias:            "%s不符合%s规则",
+	ValidAliasCycle:       "规则别名%s循环引用",
+	ValidExpr:             "%s不满足条件%s",
+	ValidExprSyntax:       "表达式%s有误: %v",
+	ValidExprEval:         "%s的条件%s无法计算: %v",
+	ValidExprStruct:       "表达式%s只能用于结构体中的字段",
+	ValidRuleMode:         "规则配置的 mode %s 有误",
+	ValidRuleType:         "规则配置中的结构体%s未注册",
+	ValidRuleField:        "结构体%v不存在字段%s",
+	ValidRequired:         "%s不能为空",
+	ValidIsEmail:          "%s不是有效的Email",
+	ValidIsMobile:         "%s不是有效的手机号码",
+	ValidIsUUID:           "%s不是有效的UUID",
+	ValidLength:           "%s的长度必须在%v到%v之间",
+	ValidLengthEq:         "%s的长度必须为%v",
+	ValidLengthLt:         "%s的长度必须小于%v",
+	ValidLengthLte:        "%s的长度不能大于%v",
+	ValidLengthGt:         "%s的长度必须大于%v",
+	ValidLengthGte:        "%s的长度不能小于%v",
+	ValidJSONType:         "%s的类型必须是%v",
+	ValidJSONTrailing:     "JSON 文档末尾有多余的内容",
+	ValidValuer:           "%s取值失败: %v",
+	ValidPresent:          "%s不能缺少",
+	ValidSchemaKeyword:    "不支持的 JSON Schema %s: %v",
+	ValidRuleParams:       "规则%s的参数个数应为%s，实际为%d",
+	ValidRuleParam:        "规则%s的参数%s格式有误",
+	ValidRuleKind:         "规则%s不适用于%s类型的字段",
 }
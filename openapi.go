@@ -0,0 +1,227 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 由校验规则生成 OpenAPI 3.1 的组件 schema 和参数，属性的 x-error-messages 为当前语言的错误信息
+// 结果中的 map 由 encoding/json 按键排序，字段按结构体中的顺序输出，便于提交和比对
+
+// 参数位置的 tag，值为参数名
+var parameterTags = []string{"path", "query", "header", "cookie"}
+
+// Parameter OpenAPI 参数
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// messageSpec 规则对应的错误信息，list 为 true 时参数作为一个列表传入
+type messageSpec struct {
+	key  string
+	list bool
+}
+
+// ruleMessages 可在文档中给出错误信息的规则，信息的参数依次为字段标题和规则参数
+var ruleMessages = map[string]messageSpec{
+	"required":      {ValidRequired, false},
+	"email":         {ValidIsEmail, false},
+	"phone":         {ValidIsMobile, false},
+	"uuid":          {ValidIsUUID, false},
+	"in":            {ValidIn, true},
+	"regex":         {ValidIsRegex, true},
+	"contains":      {ValidContains, true},
+	"contains_i":    {ValidContains, true},
+	"containsany":   {ValidContainsAny, true},
+	"containsany_i": {ValidContainsAny, true},
+	"excludes":      {ValidExcludes, true},
+	"excludes_i":    {ValidExcludes, true},
+	"excludesall":   {ValidExcludesAll, true},
+	"startswith":    {ValidStartsWith, true},
+	"startswith_i":  {ValidStartsWith, true},
+	"endswith":      {ValidEndsWith, true},
+	"endswith_i":    {ValidEndsWith, true},
+	"lowercase":     {ValidLowercase, false},
+	"uppercase":     {ValidUppercase, false},
+	"notblank":      {ValidNotBlank, false},
+	"between":       {ValidBetween, false},
+	"multiple_of":   {ValidMultipleOf, false},
+	"duration":      {ValidDurationRange, false},
+	"weekday":       {ValidWeekday, true},
+	"timeofday":     {ValidTimeOfDay, false},
+	"expr":          {ValidExpr, false},
+}
+
+// 比较规则按字段类型选择错误信息
+const (
+	boundNumber = iota // 数值
+	boundLength        // 字符串、数组、map 的长度
+	boundTime          // time.Time
+)
+
+// boundMessages 比较规则的错误信息，按 boundNumber、boundLength、boundTime 排列，与校验时的信息一致
+var boundMessages = map[string][3]string{
+	"eq":  {ValidDecimalEq, ValidLengthEq, ValidDecimalEq},
+	"lt":  {ValidDecimalLt, ValidLengthLt, ValidTimeLt},
+	"lte": {ValidDecimalLte, ValidLengthLte, ValidTimeLte},
+	"max": {ValidDecimalLte, ValidLengthLte, ValidTimeLte},
+	"gt":  {ValidDecimalGt, ValidLengthGt, ValidTimeGt},
+	"gte": {ValidDecimalGte, ValidLengthGte, ValidTimeGte},
+	"min": {ValidDecimalGte, ValidLengthGte, ValidTimeGte},
+}
+
+// schemaBoundKind 按字段的 schema 判断比较规则比较的是数值、长度还是时间
+func schemaBoundKind(prop *Schema) int {
+	switch {
+	case prop.Type == "string" && prop.Format == "date-time":
+		return boundTime
+	case prop.Type == "string", prop.Type == "array", prop.Type == "object":
+		return boundLength
+	}
+	return boundNumber
+}
+
+// ruleMessage 规则的错误信息，prop 为字段的 schema，多余的参数忽略
+func ruleMessage(rule string, title string, params []string, prop *Schema) (msg string, ok bool) {
+	if rule == "len" || rule == "len_runes" {
+		return lengthMessage(title, params, schemaBoundKind(prop) == boundLength)
+	}
+	args := make([]interface{}, len(params))
+	for i, param := range params {
+		args[i] = param
+	}
+	if keys, found := boundMessages[rule]; found {
+		kind := schemaBoundKind(prop)
+		// eq 对字符串比较的是值
+		if rule == "eq" && prop.Type == "string" {
+			kind = boundNumber
+		}
+		return formatMessage(trans(keys[kind]), title, args...), true
+	}
+	spec, ok := ruleMessages[rule]
+	if !ok {
+		return
+	}
+	if spec.list {
+		args = []interface{}{params}
+	}
+	return formatMessage(trans(spec.key), title, args...), true
+}
+
+// lengthMessage len 规则的错误信息，字符串、数组等为长度范围，数值为取值范围，"_" 表示不限
+func lengthMessage(title string, params []string, length bool) (msg string, ok bool) {
+	keys := rangeMessages
+	if length {
+		keys = lengthMessages
+	}
+	switch {
+	case len(params) == 1:
+		return formatMessage(trans(keys[0]), title, params[0]), true
+	case len(params) != 2:
+		return
+	case params[0] != VALIDATOR_IGNORE_SIGN && params[1] != VALIDATOR_IGNORE_SIGN:
+		return formatMessage(trans(keys[1]), title, params[0], params[1]), true
+	case params[0] != VALIDATOR_IGNORE_SIGN:
+		return formatMessage(trans(keys[2]), title, params[0]), true
+	case params[1] != VALIDATOR_IGNORE_SIGN:
+		return formatMessage(trans(keys[3]), title, params[1]), true
+	}
+	return
+}
+
+// formatMessage 按信息中的占位符个数补齐或截断参数
+func formatMessage(tpl string, title string, params ...interface{}) string {
+	args := append([]interface{}{title}, params...)
+	verbs := strings.Count(tpl, "%") - 2*strings.Count(tpl, "%%")
+	if len(args) > verbs {
+		args = args[:verbs]
+	}
+	for len(args) < verbs {
+		args = append(args, "")
+	}
+	return fmt.Sprintf(tpl, args...)
+}
+
+// OpenAPIComponents 生成 components.schemas，键为结构体名，带参数位置 tag 的字段不作为属性
+func (v *Validator) OpenAPIComponents(types ...interface{}) (map[string]*Schema, error) {
+	components := make(map[string]*Schema, len(types))
+	for _, t := range types {
+		rt := reflect.TypeOf(t)
+		for rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+		schema, err := v.newSchemaBuilder(true).typeSchema(rt, nil)
+		if err != nil {
+			return nil, err
+		}
+		components[rt.Name()] = schema
+	}
+	return components, nil
+}
+
+// OpenAPIParameters 生成带 path、query、header、cookie tag 的字段对应的参数，path 参数总是必填
+func (v *Validator) OpenAPIParameters(s interface{}) ([]*Parameter, error) {
+	rt := reflect.TypeOf(s)
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	b := v.newSchemaBuilder(true)
+	return b.parameters(rt, b.set.scope(rt, nil))
+}
+
+func (b *schemaBuilder) parameters(rt reflect.Type, scope *ruleScope) (params []*Parameter, err error) {
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && ft.Kind() == reflect.Struct {
+			embedded, err := b.parameters(ft, b.set.scope(ft, scope.child(sf.Name)))
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, embedded...)
+			continue
+		}
+		name, in := parameterName(sf)
+		if in == "" {
+			continue
+		}
+		prop, required, err := b.fieldSchema(rt, sf, scope)
+		if err != nil {
+			return nil, err
+		}
+		param := &Parameter{
+			Name:        name,
+			In:          in,
+			Description: prop.Title,
+			Required:    required || in == "path",
+			Schema:      prop,
+		}
+		prop.Title = ""
+		params = append(params, param)
+	}
+	return
+}
+
+// parameterName 字段的参数名和位置，in 为空表示不是参数
+func parameterName(sf reflect.StructField) (name string, in string) {
+	if sf.PkgPath != "" {
+		return
+	}
+	for _, tag := range parameterTags {
+		if name = sf.Tag.Get(tag); name != "" && name != "-" {
+			if num := strings.Index(name, ","); num != -1 {
+				name = name[:num]
+			}
+			return name, tag
+		}
+	}
+	return "", ""
+}
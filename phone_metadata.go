@@ -0,0 +1,70 @@
+package validators
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// defaultPhoneRegion phone 规则未指定地区时使用的地区
+const defaultPhoneRegion = "CN"
+
+// PhoneMeta 地区手机号码元数据
+type PhoneMeta struct {
+	CountryCode string         // 国际电话区号，不含"+"
+	Mobile      *regexp.Regexp // 本地手机号码格式
+}
+
+// phoneMu 保护 phoneMetadata，RegisterPhoneRegion 可能与校验并发执行
+var phoneMu sync.RWMutex
+
+// phoneMetadata 内置的地区手机号码元数据，可通过 RegisterPhoneRegion 更新
+var phoneMetadata = map[string]*PhoneMeta{
+	"CN": {CountryCode: "86", Mobile: phoneRegex},
+	"HK": newPhoneMeta("852", `[4-9]\d{7}`),
+	"MO": newPhoneMeta("853", `6\d{7}`),
+	"TW": newPhoneMeta("886", `0?9\d{8}`),
+	"US": newPhoneMeta("1", `[2-9]\d{2}[2-9]\d{6}`),
+	"CA": newPhoneMeta("1", `[2-9]\d{2}[2-9]\d{6}`),
+	"GB": newPhoneMeta("44", `0?7\d{9}`),
+	"JP": newPhoneMeta("81", `0?[789]0\d{8}`),
+	"KR": newPhoneMeta("82", `0?1[016-9]\d{7,8}`),
+	"SG": newPhoneMeta("65", `[89]\d{7}`),
+	"AU": newPhoneMeta("61", `0?4\d{8}`),
+	"DE": newPhoneMeta("49", `0?1[5-7]\d{8,9}`),
+	"FR": newPhoneMeta("33", `0?[67]\d{8}`),
+}
+
+func newPhoneMeta(countryCode string, pattern string) *PhoneMeta {
+	return &PhoneMeta{
+		CountryCode: countryCode,
+		Mobile:      regexp.MustCompile("^(?:" + pattern + ")$"),
+	}
+}
+
+// RegisterPhoneRegion 注册或更新地区手机号码规则，pattern 为本地号码格式(不含国际区号)
+func RegisterPhoneRegion(region string, countryCode string, pattern string) {
+	meta := newPhoneMeta(countryCode, pattern)
+	phoneMu.Lock()
+	phoneMetadata[strings.ToUpper(region)] = meta
+	phoneMu.Unlock()
+}
+
+// lookupPhoneMeta 查找地区手机号码元数据
+func lookupPhoneMeta(region string) (meta *PhoneMeta, ok bool) {
+	phoneMu.RLock()
+	meta, ok = phoneMetadata[strings.ToUpper(region)]
+	phoneMu.RUnlock()
+	return
+}
+
+// match 校验手机号码，允许带"+国际区号"前缀
+func (m *PhoneMeta) match(phone string) bool {
+	if strings.HasPrefix(phone, "+") {
+		if !strings.HasPrefix(phone[1:], m.CountryCode) {
+			return false
+		}
+		phone = strings.TrimLeft(phone[1+len(m.CountryCode):], " -")
+	}
+	return m.Mobile.MatchString(phone)
+}
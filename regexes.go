@@ -47,7 +47,10 @@ const (
 	uRLEncodedRegexString            = `(%[A-Fa-f0-9]{2})`
 	hTMLEncodedRegexString           = `&#[x]?([0-9a-fA-F]{2})|(&gt)|(&lt)|(&quot)|(&amp)+[;]?`
 	hTMLRegexString                  = `<[/]?([a-zA-Z]+).*?>`
-	phoneRegexString                 = `^1([38][0-9]|4[579]|5[^4]|6[6]|7[1-35-8]|9[189])\d{8}$`
+	phoneRegexString                 = `^1[3-9]\d{9}$`
+	e164RegexString                  = `^\+[1-9]\d{1,14}$`
+	ibanRegexString                  = `^[A-Z]{2}\d{2}[A-Z0-9]{11,30}$`
+	landlineRegexString              = `^0(?:10|2\d|[3-9]\d{2})-?[2-9]\d{6,7}(?:-\d{1,6})?$` // 区号-号码-分机号
 
 	////url验证正则
 	//urlRegexString = `^(http|ftp|https):\/\/[\w\-_]+(\.[\w\-_]+)+([\w\-\.,@?^=%&:/~\+#]*[\w\-\@?^=%&/~\+#])?$`
@@ -105,4 +108,7 @@ var (
 	hTMLEncodedRegex           = regexp.MustCompile(hTMLEncodedRegexString)
 	hTMLRegex                  = regexp.MustCompile(hTMLRegexString)
 	phoneRegex                 = regexp.MustCompile(phoneRegexString)
+	e164Regex                  = regexp.MustCompile(e164RegexString)
+	landlineRegex              = regexp.MustCompile(landlineRegexString)
+	ibanRegex                  = regexp.MustCompile(ibanRegexString)
 )
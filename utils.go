@@ -157,6 +157,8 @@ func inArray(need interface{}, needArr ...interface{}) bool {
 func isZeroValue(val reflect.Value) bool {
 	typeKind := val.Kind()
 	switch typeKind {
+	case reflect.Invalid:
+		return true
 	case reflect.String, reflect.Array:
 		return val.Len() == 0
 	case reflect.Map, reflect.Slice:
@@ -169,8 +171,18 @@ func isZeroValue(val reflect.Value) bool {
 		return val.Uint() == 0
 	case reflect.Float32, reflect.Float64:
 		return val.Float() == 0
-	case reflect.Interface, reflect.Ptr:
+	case reflect.Complex64, reflect.Complex128:
+		return val.Complex() == 0
+	case reflect.Interface, reflect.Ptr, reflect.Chan, reflect.Func, reflect.UnsafePointer:
 		return val.IsNil()
+	case reflect.Struct:
+		// 逐个字段判断，不调用 Interface，未导出字段不会 panic
+		for i := 0; i < val.NumField(); i++ {
+			if !isZeroValue(val.Field(i)) {
+				return false
+			}
+		}
+		return true
 	}
 	return reflect.DeepEqual(val.Interface(), reflect.Zero(val.Type()).Interface())
 }
@@ -1,9 +1,18 @@
 package validators
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestRequired(t *testing.T) {
@@ -81,6 +90,28 @@ func TestRequired(t *testing.T) {
 			t.Errorf("Expected required,value %v,err %v,expected  %v", test.param, err, test.expected)
 		}
 	}
+
+	// 结构体中含未导出的 chan、func 字段
+	type inner struct {
+		ch   chan int
+		fn   func()
+		name string
+	}
+	testStruct := []struct {
+		Param    inner `validate:"required"`
+		expected bool
+	}{
+		{inner{ch: make(chan int)}, true},
+		{inner{fn: func() {}}, true},
+		{inner{name: "a"}, true},
+		{inner{}, false},
+	}
+	for _, test := range testStruct {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected required,value %+v,err %v,expected  %v", test.Param, err, test.expected)
+		}
+	}
 }
 
 func TestIn2(t *testing.T) {
@@ -542,3 +573,1878 @@ func TestMuti1(t *testing.T) {
 		}
 	}
 }
+
+func TestBusinessCode(t *testing.T) {
+	validator := New()
+	testUSCC := []struct {
+		param    string `validate:"uscc"`
+		expected bool
+	}{
+		{"91350100M000100Y43", true},
+		{"91110000600037341L", true},
+		{"91350100M000100Y44", false},
+		{"91350100M000100Y4", false},
+		{"9135010OM000100Y43", false},
+	}
+	for _, test := range testUSCC {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected uscc,value %v,err %v", test.param, err)
+		}
+	}
+
+	testOrgCode := []struct {
+		param    string `validate:"orgcode"`
+		expected bool
+	}{
+		{"60003734-1", true},
+		{"600037341", true},
+		{"M000100Y4", true},
+		{"60003734-2", false},
+		{"6000373-41", false},
+	}
+	for _, test := range testOrgCode {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected orgcode,value %v,err %v", test.param, err)
+		}
+	}
+
+	testTaxNo := []struct {
+		param    string `validate:"taxno"`
+		expected bool
+	}{
+		{"110105600037341", true},
+		{"91110000600037341L", true},
+		{"11010519491231002X01", true},
+		{"110105600037342", false},
+		{"11010519491231002101", false},
+		{"1101056000373", false},
+	}
+	for _, test := range testTaxNo {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected taxno,value %v,err %v", test.param, err)
+		}
+	}
+}
+
+func TestPhone(t *testing.T) {
+	validator := New()
+	testCN := []struct {
+		param    string `validate:"phone"`
+		expected bool
+	}{
+		{"13800138000", true},
+		{"19912345678", true},
+		{"16612345678", true},
+		{"+8613800138000", true},
+		{"12800138000", false},
+		{"+85213800138000", false},
+		{"1380013800", false},
+	}
+	for _, test := range testCN {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected phone,value %v,err %v", test.param, err)
+		}
+	}
+
+	testRegion := []struct {
+		param    string `validate:"phone=HK,US"`
+		expected bool
+	}{
+		{"91234567", true},
+		{"+852 51234567", true},
+		{"2025550123", true},
+		{"+12025550123", true},
+		{"13800138000", false},
+		{"1234567", false},
+	}
+	for _, test := range testRegion {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected phone,value %v,err %v", test.param, err)
+		}
+	}
+
+	testUnknown := struct {
+		param string `validate:"phone=XX"`
+	}{"13800138000"}
+	if errs := validator.Struct(testUnknown); len(errs) != 1 {
+		t.Errorf("Expected phone region error,value %v", testUnknown.param)
+	} else if _, ok := errs[0].(*TagError); !ok {
+		t.Errorf("Expected TagError for unknown phone region,got %T", errs[0])
+	}
+
+	// 注册地区与校验并发执行
+	testRegister := struct {
+		param string `validate:"phone=XT"`
+	}{"51234567"}
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			RegisterPhoneRegion("XT", "999", `[5-9]\d{7}`)
+		}
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		validator.Struct(testRegister)
+	}
+	<-done
+	if err := validator.Struct(testRegister); err != nil {
+		t.Errorf("Expected registered phone region,value %v,err %v", testRegister.param, err)
+	}
+
+	testE164 := []struct {
+		param    string `validate:"e164"`
+		expected bool
+	}{
+		{"+8613800138000", true},
+		{"+14155552671", true},
+		{"8613800138000", false},
+		{"+0123456", false},
+		{"+1234567890123456", false},
+	}
+	for _, test := range testE164 {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected e164,value %v,err %v", test.param, err)
+		}
+	}
+
+	testLandline := []struct {
+		param    string `validate:"landline"`
+		expected bool
+	}{
+		{"010-62345678", true},
+		{"02162345678", true},
+		{"0571-8888888-123", true},
+		{"62345678", false},
+		{"010-02345678", false},
+		{"13800138000", false},
+	}
+	for _, test := range testLandline {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected landline,value %v,err %v", test.param, err)
+		}
+	}
+}
+
+func TestPayment(t *testing.T) {
+	validator := New()
+	testLuhn := []struct {
+		param    string `validate:"luhn"`
+		expected bool
+	}{
+		{"79927398713", true},
+		{"4111111111111111", true},
+		{"79927398710", false},
+		{"7992a398713", false},
+	}
+	for _, test := range testLuhn {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected luhn,value %v,err %v", test.param, err)
+		}
+	}
+
+	testBankCard := []struct {
+		param    string `validate:"bankcard"`
+		expected bool
+		check    string
+	}{
+		{"6222021234567890123", true, ""},
+		{"6222 0212 3456 7890", true, ""},
+		{"4111111111111111", true, ""},
+		{"4111111111111112", false, checkLuhn},
+		{"62220212345", false, checkLength},
+		{"6222o21234567890", false, checkFormat},
+	}
+	for _, test := range testBankCard {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected bankcard,value %v,err %v", test.param, err)
+			continue
+		}
+		if err != nil {
+			fe, ok := err[0].(*FieldError)
+			if !ok || len(fe.Checks) != 1 || fe.Checks[0] != test.check {
+				t.Errorf("Expected bankcard check %v,value %v,err %#v", test.check, test.param, err[0])
+			}
+		}
+	}
+
+	testUnionPay := []struct {
+		param    string `validate:"bankcard=unionpay"`
+		expected bool
+	}{
+		{"6222021234567890123", true},
+		{"4111111111111111", false},
+	}
+	for _, test := range testUnionPay {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected bankcard,value %v,err %v", test.param, err)
+		}
+	}
+
+	testBankBrand := struct {
+		param string `validate:"bankcard=visa"`
+	}{"4111111111111111"}
+	if err := validator.Struct(testBankBrand); err == nil {
+		t.Errorf("Expected bankcard brand error,value %v", testBankBrand.param)
+	}
+
+	testCreditCard := []struct {
+		param    string `validate:"credit_card=visa,mastercard"`
+		expected bool
+	}{
+		{"4111111111111111", true},
+		{"5555555555554444", true},
+		{"2223003122003222", true},
+		{"378282246310005", false},
+		{"4111111111111112", false},
+	}
+	for _, test := range testCreditCard {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected credit_card,value %v,err %v", test.param, err)
+		}
+	}
+
+	testAnyCard := []struct {
+		param    string `validate:"credit_card"`
+		expected bool
+	}{
+		{"378282246310005", true},
+		{"6200000000000005", true},
+		{"6222021234567890123", true},
+		{"1234567812345670", false},
+	}
+	for _, test := range testAnyCard {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected credit_card,value %v,err %v", test.param, err)
+		}
+	}
+
+	testIBAN := []struct {
+		param    string `validate:"iban"`
+		expected bool
+		check    string
+	}{
+		{"GB82WEST12345698765432", true, ""},
+		{"DE89 3704 0044 0532 0130 00", true, ""},
+		{"GB82WEST12345698765433", false, checkChecksum},
+		{"DE8937040044053201300", false, checkCountry},
+		{"82GBWEST12345698765432", false, checkFormat},
+	}
+	for _, test := range testIBAN {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected iban,value %v,err %v", test.param, err)
+			continue
+		}
+		if err != nil {
+			fe, ok := err[0].(*FieldError)
+			if !ok || fe.Checks[0] != test.check {
+				t.Errorf("Expected iban check %v,value %v,err %#v", test.check, test.param, err[0])
+			}
+		}
+	}
+}
+
+func TestPassword(t *testing.T) {
+	validator := New()
+	testPolicy := []struct {
+		param    string `validate:"password=min:8,upper:1,lower:1,digit:1,special:1,norepeat:3"`
+		expected bool
+		checks   []string
+	}{
+		{"Passw0rd!", true, nil},
+		{"Aa1!Aa1!", true, nil},
+		{"password", false, []string{"upper", "digit", "special"}},
+		{"Pa1!", false, []string{"min"}},
+		{"Paaassw0rd!", false, []string{"norepeat"}},
+		{"", false, []string{"min", "upper", "lower", "digit", "special"}},
+	}
+	for _, test := range testPolicy {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected password,value %v,err %v", test.param, err)
+			continue
+		}
+		if err != nil {
+			fe, ok := err[0].(*FieldError)
+			if !ok || !reflect.DeepEqual(fe.Checks, test.checks) {
+				t.Errorf("Expected password checks %v,value %v,err %#v", test.checks, test.param, err[0])
+			}
+		}
+	}
+
+	testEntropy := []struct {
+		param    string `validate:"password=entropy:50"`
+		expected bool
+	}{
+		{"correct horse battery staple", true},
+		{"Tr0ub4dor&3", true},
+		{"abcdefgh", false},
+		{"12345678", false},
+	}
+	for _, test := range testEntropy {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected password,value %v,err %v", test.param, err)
+		}
+	}
+
+	f, err := ioutil.TempFile("", "banned")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("# common passwords\nPassw0rd!\n\nqwerty123\n")
+	f.Close()
+	banned, err := LoadBannedPasswords(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator = New().SetBannedPasswords(banned)
+	testBanned := []struct {
+		param    string `validate:"password"`
+		expected bool
+	}{
+		{"passw0rd!", false},
+		{"QWERTY123", false},
+		{"Xk9#mQ2$vL", true},
+	}
+	for _, test := range testBanned {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected password,value %v,err %v", test.param, err)
+		}
+	}
+}
+
+func TestRegex(t *testing.T) {
+	validator := New().MustRegisterPattern("order_no", `^SO\d{8}$`)
+	testNamed := []struct {
+		param    string `validate:"regex=order_no"`
+		expected bool
+	}{
+		{"SO20200101", true},
+		{"SO2020010", false},
+		{"PO20200101", false},
+	}
+	for _, test := range testNamed {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected regex,value %v,err %v", test.param, err)
+		}
+	}
+
+	testInline := []struct {
+		param    string `validate:"regex=/^[a-z]{2,4}\\;\\d+$/;len=_,8"`
+		expected bool
+	}{
+		{"ab;1", true},
+		{"abcd;123", true},
+		{"a;1", false},
+		{"abcd;1234", false},
+		{"ab,1", false},
+	}
+	for _, test := range testInline {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected regex,value %v,err %v", test.param, err)
+		}
+	}
+
+	testNotExist := struct {
+		param string `validate:"regex=unknown"`
+	}{"SO20200101"}
+	if err := validator.Struct(testNotExist); err == nil {
+		t.Errorf("Expected regex not exist error,value %v", testNotExist.param)
+	}
+
+	if err := validator.RegisterPattern("broken", `^(SO`); err == nil {
+		t.Errorf("Expected invalid pattern error")
+	}
+	if _, ok := validator.pattern("broken"); ok {
+		t.Errorf("Expected invalid pattern not to be registered")
+	}
+
+	if parts := splitEscaped(`a\,b,c\d`, VALIDATOR_RANGE_SPLIT); !reflect.DeepEqual(parts, []string{"a,b", `c\d`}) {
+		t.Errorf("Expected escaped split,got %q", parts)
+	}
+}
+
+func TestStringContent(t *testing.T) {
+	validator := New()
+	testContains := []struct {
+		param    string `validate:"contains=中文,go"`
+		expected bool
+	}{
+		{"学习go和中文", true},
+		{"学习Go和中文", false},
+		{"go", false},
+	}
+	for _, test := range testContains {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected contains,value %v,err %v", test.param, err)
+		}
+	}
+
+	testContainsFold := []struct {
+		param    string `validate:"contains_i=GO"`
+		expected bool
+	}{
+		{"学习Go", true},
+		{"golang", true},
+		{"java", false},
+	}
+	for _, test := range testContainsFold {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected contains_i,value %v,err %v", test.param, err)
+		}
+	}
+
+	testContainsAny := []struct {
+		param    string `validate:"containsany=@,\\,"`
+		expected bool
+	}{
+		{"a@b", true},
+		{"a,b", true},
+		{"ab", false},
+	}
+	for _, test := range testContainsAny {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected containsany,value %v,err %v", test.param, err)
+		}
+	}
+
+	testExcludes := []struct {
+		param    string `validate:"excludes_i=admin,root;excludesall=<>"`
+		expected bool
+	}{
+		{"zhangsan", true},
+		{"ADMIN01", false},
+		{"myroot", false},
+		{"<b>", false},
+	}
+	for _, test := range testExcludes {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected excludes,value %v,err %v", test.param, err)
+		}
+	}
+
+	testAffix := []struct {
+		param    string `validate:"startswith_i=http://,https://;endswith=.jpg,.png"`
+		expected bool
+	}{
+		{"https://a.com/1.jpg", true},
+		{"HTTP://a.com/1.png", true},
+		{"ftp://a.com/1.png", false},
+		{"https://a.com/1.JPG", false},
+	}
+	for _, test := range testAffix {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected startswith/endswith,value %v,err %v", test.param, err)
+		}
+	}
+
+	testCase := []struct {
+		lower    string `validate:"lowercase"`
+		upper    string `validate:"uppercase"`
+		expected bool
+	}{
+		{"abc中文1", "ABC中文1", true},
+		{"Abc", "ABC", false},
+		{"abc", "ABc", false},
+	}
+	for _, test := range testCase {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected lowercase/uppercase,value %v %v,err %v", test.lower, test.upper, err)
+		}
+	}
+
+	testNotBlank := []struct {
+		param    string `validate:"notblank"`
+		expected bool
+	}{
+		{" a ", true},
+		{"", false},
+		{" \t　", false},
+	}
+	for _, test := range testNotBlank {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected notblank,value %q,err %v", test.param, err)
+		}
+	}
+}
+
+func TestLengthMode(t *testing.T) {
+	validator := New()
+	testBytes := []struct {
+		param    string `validate:"len_bytes=_,6"`
+		expected bool
+	}{
+		{"abcdef", true},
+		{"中文", true},
+		{"中文a1", false},
+		{"abcdefg", false},
+	}
+	for _, test := range testBytes {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected len_bytes,value %v,err %v", test.param, err)
+		}
+	}
+
+	testRunes := []struct {
+		param    string `validate:"len_runes=2"`
+		expected bool
+	}{
+		{"中文", true},
+		{"ab", true},
+		{"中文a", false},
+	}
+	for _, test := range testRunes {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected len_runes,value %v,err %v", test.param, err)
+		}
+	}
+
+	testGraphemes := []struct {
+		param    string `validate:"len_graphemes=1"`
+		expected bool
+	}{
+		{"👨‍👩‍👧‍👦", true},
+		{"🇨🇳", true},
+		{"👍🏽", true},
+		{"e\u0301", true},
+		{"\r\n", true},
+		{"한", true},
+		{"\u1112\u1161\u11ab", true},
+		{"🇨🇳🇺🇸", false},
+		{"ab", false},
+		{"", false},
+	}
+	for _, test := range testGraphemes {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected len_graphemes,value %q,err %v", test.param, err)
+		}
+	}
+
+	testSlice := []struct {
+		param    []string `validate:"len_bytes=1,2"`
+		expected bool
+	}{
+		{[]string{"中文"}, true},
+		{[]string{}, false},
+	}
+	for _, test := range testSlice {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected len_bytes,value %v,err %v", test.param, err)
+		}
+	}
+}
+
+func TestTimeCompare(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	validator := New().SetClock(func() time.Time { return now })
+
+	testAbsolute := []struct {
+		Param    time.Time `validate:"gt=2020-01-01;lte=2020-06-01 12:00:00"`
+		expected bool
+	}{
+		{time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), true},
+		{now, true},
+		{time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{now.Add(time.Second), false},
+	}
+	for _, test := range testAbsolute {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected gt/lte,value %v,err %v", test.Param, err)
+		}
+	}
+
+	testRelative := []struct {
+		Birthday time.Time  `validate:"before=now-18y"`
+		Delivery *time.Time `validate:"after=now+24h"`
+		expected bool
+	}{
+		{now.AddDate(-20, 0, 0), timePtr(now.Add(48 * time.Hour)), true},
+		{now.AddDate(-17, 0, 0), timePtr(now.Add(48 * time.Hour)), false},
+		{now.AddDate(-20, 0, 0), timePtr(now.Add(time.Hour)), false},
+	}
+	for _, test := range testRelative {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected before/after,value %v %v,err %v", test.Birthday, test.Delivery, err)
+		}
+	}
+
+	testField := []struct {
+		StartTime time.Time
+		EndTime   time.Time `validate:"after=StartTime"`
+		expected  bool
+	}{
+		{now, now.Add(time.Hour), true},
+		{now, now, false},
+		{now, now.Add(-time.Hour), false},
+	}
+	for _, test := range testField {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected after field,value %v %v,err %v", test.StartTime, test.EndTime, err)
+		}
+	}
+
+	testBadParam := struct {
+		Param time.Time `validate:"gt=yesterday"`
+	}{now}
+	if err := validator.Struct(testBadParam); err == nil {
+		t.Errorf("Expected time param error")
+	}
+
+	// 未导出的 time.Time 字段无法读取，返回类型错误
+	testUnexported := struct {
+		param time.Time `validate:"gt=2020-01-01"`
+	}{now}
+	if err := validator.Struct(testUnexported); err == nil {
+		t.Errorf("Expected time type error for unexported field")
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestSchedule(t *testing.T) {
+	validator := New()
+	testDuration := []struct {
+		Timeout  time.Duration `validate:"duration=1m,2h"`
+		Interval string        `validate:"duration=_,30s"`
+		expected bool
+	}{
+		{time.Minute, "30s", true},
+		{90 * time.Minute, "500ms", true},
+		{30 * time.Second, "1s", false},
+		{3 * time.Hour, "1s", false},
+		{time.Hour, "1m", false},
+		{time.Hour, "abc", false},
+	}
+	for _, test := range testDuration {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected duration,value %v %v,err %v", test.Timeout, test.Interval, err)
+		}
+	}
+
+	testDate := []struct {
+		Day      string `validate:"date"`
+		Month    string `validate:"date=2006/01"`
+		expected bool
+	}{
+		{"2020-02-29", "2020/02", true},
+		{"2019-02-29", "2020/02", false},
+		{"2020-02-01 10:00", "2020/02", false},
+		{"2020-02-01", "2020-02", false},
+	}
+	for _, test := range testDate {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected date,value %v %v,err %v", test.Day, test.Month, err)
+		}
+	}
+
+	testWeekday := []struct {
+		Delivery time.Time `validate:"weekday=mon,tue,Asia/Shanghai"`
+		Day      string    `validate:"weekday=sat,sunday"`
+		expected bool
+	}{
+		{time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC), "2020-06-06", true},
+		{time.Date(2020, 6, 1, 20, 0, 0, 0, time.UTC), "2020-06-07", true},
+		{time.Date(2020, 6, 2, 20, 0, 0, 0, time.UTC), "2020-06-06", false},
+		{time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC), "2020-06-05", false},
+	}
+	for _, test := range testWeekday {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected weekday,value %v %v,err %v", test.Delivery, test.Day, err)
+		}
+	}
+
+	testWeekdayName := struct {
+		Day string `validate:"weekday=mon,funday"`
+	}{"2020-06-01"}
+	if err := validator.Struct(testWeekdayName); err == nil || !strings.Contains(err[0].Error(), "funday") || strings.Contains(err[0].Error(), "时区") {
+		t.Errorf("Expected unknown weekday error,got %v", err)
+	}
+
+	testTimeOfDay := []struct {
+		Delivery time.Time `validate:"timeofday=09:00,18:00,Asia/Shanghai"`
+		Night    string    `validate:"timeofday=22:00,06:00"`
+		expected bool
+	}{
+		{time.Date(2020, 6, 1, 1, 0, 0, 0, time.UTC), "23:30", true},
+		{time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC), "05:59:59", true},
+		{time.Date(2020, 6, 1, 10, 0, 1, 0, time.UTC), "06:00", false},
+		{time.Date(2020, 6, 1, 0, 59, 0, 0, time.UTC), "06:00", false},
+		{time.Date(2020, 6, 1, 1, 0, 0, 0, time.UTC), "12:00", false},
+		{time.Date(2020, 6, 1, 1, 0, 0, 0, time.UTC), "25:00", false},
+	}
+	for _, test := range testTimeOfDay {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected timeofday,value %v %v,err %v", test.Delivery, test.Night, err)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	validator := New()
+	testDecimal := []struct {
+		Amount   string  `validate:"decimal=12,2"`
+		Price    float64 `validate:"decimal=5,2"`
+		expected bool
+	}{
+		{"9999999999.99", 999.99, true},
+		{"-0.5", 0.1, true},
+		{"12345678901.00", 1, false},
+		{"1.234", 1, false},
+		{"1e5", 1, false},
+		{"1", 0.125, false},
+	}
+	for _, test := range testDecimal {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected decimal,value %v %v,err %v", test.Amount, test.Price, err)
+		}
+	}
+
+	testBetween := []struct {
+		Amount   string `validate:"between=0.01,99999999999999999.99"`
+		expected bool
+	}{
+		{"0.01", true},
+		{"99999999999999999.99", true},
+		{"0.009", false},
+		{"100000000000000000.00", false},
+		{"abc", false},
+	}
+	for _, test := range testBetween {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected between,value %v,err %v", test.Amount, err)
+		}
+	}
+
+	testMultipleOf := []struct {
+		Amount   float64 `validate:"multiple_of=0.05"`
+		Quantity string  `validate:"multiple_of=10"`
+		expected bool
+	}{
+		{0.15, "120", true},
+		{19.95, "0", true},
+		{0.16, "120", false},
+		{0.15, "125", false},
+	}
+	for _, test := range testMultipleOf {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected multiple_of,value %v %v,err %v", test.Amount, test.Quantity, err)
+		}
+	}
+
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	testBig := []struct {
+		Int      *big.Int `validate:"gt=123456789012345678901234567889"`
+		Rat      *big.Rat `validate:"lte=0.3;decimal=3,2"`
+		expected bool
+	}{
+		{huge, big.NewRat(3, 10), true},
+		{new(big.Int).Sub(huge, big.NewInt(1)), big.NewRat(3, 10), false},
+		{huge, big.NewRat(301, 1000), false},
+		{huge, big.NewRat(1, 3), false},
+	}
+	for _, test := range testBig {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected big,value %v %v,err %v", test.Int, test.Rat, err)
+		}
+	}
+
+	// 未导出字段返回错误，不 panic
+	testUnexported := struct {
+		amount   []int    `validate:"decimal=5,2"`
+		rat      *big.Rat `validate:"between=0,1"`
+		duration bool     `validate:"duration=1s,_"`
+	}{[]int{1}, big.NewRat(1, 2), true}
+	if err := validator.Value(testUnexported); len(err) != 3 {
+		t.Errorf("Expected 3 errors for unexported fields,got %v", err)
+	}
+}
+
+type optionalInt struct {
+	Value int
+	Set   bool
+}
+
+type amount string
+
+func (a amount) Value() (driver.Value, error) {
+	return string(a), nil
+}
+
+type brokenValuer string
+
+func (b brokenValuer) Value() (driver.Value, error) {
+	return nil, errors.New("broken")
+}
+
+func TestCustomType(t *testing.T) {
+	validator := New()
+	testNull := []struct {
+		Name     sql.NullString  `validate:"required;len=1,5"`
+		Age      sql.NullInt64   `validate:"gte=10"`
+		Score    sql.NullFloat64 `validate:"lte=100"`
+		expected bool
+	}{
+		{sql.NullString{String: "abc", Valid: true}, sql.NullInt64{Int64: 12, Valid: true}, sql.NullFloat64{Float64: 99.5, Valid: true}, true},
+		{sql.NullString{String: "abc", Valid: true}, sql.NullInt64{}, sql.NullFloat64{}, true},
+		{sql.NullString{}, sql.NullInt64{Int64: 12, Valid: true}, sql.NullFloat64{}, false},
+		{sql.NullString{String: "abcdef", Valid: true}, sql.NullInt64{}, sql.NullFloat64{}, false},
+		{sql.NullString{String: "abc", Valid: true}, sql.NullInt64{Int64: 9, Valid: true}, sql.NullFloat64{}, false},
+		{sql.NullString{String: "abc", Valid: true}, sql.NullInt64{}, sql.NullFloat64{Float64: 100.5, Valid: true}, false},
+	}
+	for _, test := range testNull {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected sql null,value %v %v %v,err %v", test.Name, test.Age, test.Score, err)
+		}
+	}
+
+	validator.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if opt := field.Interface().(optionalInt); opt.Set {
+			return opt.Value
+		}
+		return nil
+	}, optionalInt{})
+	testCustom := []struct {
+		Quantity optionalInt `validate:"len=1,99"`
+		Price    amount      `validate:"between=0.01,999.99"`
+		expected bool
+	}{
+		{optionalInt{Value: 10, Set: true}, "9.99", true},
+		{optionalInt{}, "9.99", true},
+		{optionalInt{Value: 100, Set: true}, "9.99", false},
+		{optionalInt{Value: 10, Set: true}, "1000", false},
+	}
+	for _, test := range testCustom {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected custom type,value %v %v,err %v", test.Quantity, test.Price, err)
+		}
+	}
+
+	// 接口类型的字段为 nil 时按空值处理，Value() 出错时返回错误
+	testValuer := []struct {
+		Price driver.Valuer `validate:"len=_,5"`
+		Code  brokenValuer  `validate:"len=_,5"`
+	}{
+		{nil, ""},
+		{amount("abc"), ""},
+	}
+	for _, test := range testValuer {
+		errs := validator.Struct(test)
+		if len(errs) != 1 || errs[0].(*FieldError).Path != "Code" {
+			t.Errorf("Expected broken valuer error,got %v", errs)
+		}
+	}
+}
+
+type gender string
+
+func (g gender) IsValid() bool {
+	return g == "male" || g == "female"
+}
+
+type level int
+
+func (l level) Values() []level {
+	return []level{1, 2, 3}
+}
+
+func TestEnum(t *testing.T) {
+	validator := New().RegisterEnum("gender", "male", "female").RegisterEnum("status", 0, 1, 2)
+	tests := []struct {
+		Gender   string  `validate:"in=@gender"`
+		Status   int     `validate:"in=@status,9"`
+		Self     gender  `validate:"in"`
+		Levels   []level `validate:"in"`
+		expected bool
+	}{
+		{"male", 1, "female", []level{1, 3}, true},
+		{"other", 1, "female", []level{1}, false},
+		{"female", 9, "male", []level{2}, true},
+		{"female", 3, "male", []level{2}, false},
+		{"female", 2, "other", []level{2}, false},
+		{"female", 2, "male", []level{2, 4}, false},
+	}
+	for _, test := range tests {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected enum,value %v %v %v %v,err %v", test.Gender, test.Status, test.Self, test.Levels, err)
+		}
+	}
+
+	err := validator.Struct(struct {
+		Gender string `validate:"in=@sex"`
+	}{"male"})
+	if err == nil {
+		t.Errorf("Expected unregistered enum error")
+	}
+
+	// 无法读取枚举值时返回 tag 错误
+	for _, s := range []interface{}{
+		struct {
+			Plain int `validate:"in"`
+		}{1},
+		struct {
+			self gender `validate:"in"`
+		}{"male"},
+	} {
+		err = validator.Struct(s)
+		if len(err) != 1 {
+			t.Errorf("Expected enum tag error,value %+v,got %v", s, err)
+			continue
+		}
+		if _, ok := err[0].(*TagError); !ok {
+			t.Errorf("Expected *TagError,value %+v,got %#v", s, err[0])
+		}
+	}
+
+	// 注册枚举与校验并发执行
+	testRegister := struct {
+		Color string `validate:"in=@color"`
+	}{"red"}
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			validator.RegisterEnum("color", "red", "green")
+		}
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		validator.Struct(testRegister)
+		validator.JSONSchema(testRegister)
+	}
+	<-done
+	if err := validator.Struct(testRegister); err != nil {
+		t.Errorf("Expected registered enum,value %v,err %v", testRegister.Color, err)
+	}
+}
+
+type uniqItem struct {
+	ID   int
+	Name string
+}
+
+type uniqGroup struct {
+	IDs []int
+}
+
+func TestUniqueField(t *testing.T) {
+	validator := New()
+	tests := []struct {
+		Items    []uniqItem           `validate:"unique=ID"`
+		Ptrs     []*uniqItem          `validate:"unique=Name"`
+		Tags     []string             `validate:"unique_i"`
+		Values   map[string]uniqGroup `validate:"unique"`
+		expected bool
+	}{
+		{[]uniqItem{{1, "a"}, {2, "a"}}, []*uniqItem{{1, "a"}, {1, "b"}}, []string{"a", "B"}, map[string]uniqGroup{"x": {[]int{1}}, "y": {[]int{2}}}, true},
+		{[]uniqItem{{1, "a"}, {1, "b"}}, nil, nil, nil, false},
+		{nil, []*uniqItem{{1, "a"}, {2, "a"}}, nil, nil, false},
+		{nil, []*uniqItem{{1, "a"}, nil, nil, {2, "b"}}, nil, nil, true},
+		{nil, []*uniqItem{nil, {1, "a"}, nil, {2, "a"}}, nil, nil, false},
+		{nil, nil, []string{"a", "A"}, nil, false},
+		{nil, nil, nil, map[string]uniqGroup{"x": {[]int{1}}, "y": {[]int{1}}}, false},
+	}
+	for _, test := range tests {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected unique field,value %v %v %v %v,err %v", test.Items, test.Ptrs, test.Tags, test.Values, err)
+		}
+	}
+
+	err := validator.Struct(struct {
+		Items []uniqItem `validate:"unique=ID"`
+	}{[]uniqItem{{1, "a"}, {2, "b"}, {1, "c"}, {2, "d"}}})
+	if len(err) != 1 {
+		t.Fatalf("Expected one unique error,err %v", err)
+	}
+	fe, ok := err[0].(*FieldError)
+	if !ok || !reflect.DeepEqual(fe.Duplicates, []string{"2", "3"}) {
+		t.Errorf("Expected duplicates [2 3],err %#v", err[0])
+	}
+}
+
+func TestRuleComposition(t *testing.T) {
+	validator := New()
+	tests := []struct {
+		Account  string `validate:"email|phone"`
+		Role     string `validate:"!in=admin,root"`
+		Code     string `validate:"(len=4,4;number)|regex=/^[a-z]+\\|x$/"`
+		expected bool
+	}{
+		{"a@b.com", "user", "1234", true},
+		{"13812345678", "user", "ab|x", true},
+		{"abc", "user", "1234", false},
+		{"a@b.com", "root", "1234", false},
+		{"a@b.com", "user", "12345", false},
+		{"a@b.com", "user", "abcd", false},
+	}
+	for _, test := range tests {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected rule composition,value %v %v %v,err %v", test.Account, test.Role, test.Code, err)
+		}
+	}
+
+	err := validator.Struct(struct {
+		Account string `validate:"email|phone"`
+	}{"abc"})
+	if len(err) != 1 {
+		t.Fatalf("Expected one composition error,err %v", err)
+	}
+	fe, ok := err[0].(*FieldError)
+	if !ok || !reflect.DeepEqual(fe.Checks, []string{"email", "phone"}) {
+		t.Errorf("Expected alternatives [email phone],err %#v", err[0])
+	}
+
+	err = validator.Struct(struct {
+		Account string `validate:"(email|phone"`
+	}{"abc"})
+	if len(err) != 1 {
+		t.Errorf("Expected syntax error,err %v", err)
+	}
+}
+
+func TestAlias(t *testing.T) {
+	validator := New().RegisterAlias("username", "required;len=3,32;lowercase").RegisterAlias("account", "email|phone")
+	tests := []struct {
+		Name     string `validate:"username"`
+		Login    string `validate:"account;!in=admin@a.com"`
+		expected bool
+	}{
+		{"alice", "a@b.com", true},
+		{"al", "13812345678", false},
+		{"Alice", "a@b.com", false},
+		{"alice", "abc", false},
+		{"alice", "admin@a.com", false},
+	}
+	for _, test := range tests {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected alias,value %v %v,err %v", test.Name, test.Login, err)
+		}
+	}
+
+	err := validator.Struct(struct {
+		Name string `validate:"username"`
+	}{"al"})
+	if len(err) != 1 {
+		t.Fatalf("Expected one alias error,err %v", err)
+	}
+	fe, ok := err[0].(*FieldError)
+	if !ok || fe.Rule != "username" || !reflect.DeepEqual(fe.Checks, []string{"len=3,32"}) {
+		t.Errorf("Expected alias username failing len=3,32,err %#v", err[0])
+	}
+
+	validator.RegisterAlias("loop", "required;loop")
+	err = validator.Struct(struct {
+		Name string `validate:"loop"`
+	}{"a"})
+	if len(err) != 1 {
+		t.Errorf("Expected alias cycle error,err %v", err)
+	}
+
+	// 不允许空值时，按展开后的规则判断是否含 required
+	strict := New().SetAllowEmpty(false).RegisterAlias("username", "required;len=3,32")
+	err = strict.Struct(struct {
+		Name  string `validate:"username"`
+		Title string `validate:"regex=/^required$/"`
+	}{})
+	if len(err) != 1 {
+		t.Fatalf("Expected only the aliased required to fail,err %v", err)
+	}
+	if fe, ok := err[0].(*FieldError); !ok || fe.Path != "Name" {
+		t.Errorf("Expected alias required error on Name,err %#v", err[0])
+	}
+}
+
+type exprAddress struct {
+	City string
+}
+
+type exprInner struct {
+	X int
+}
+
+func TestExpr(t *testing.T) {
+	validator := New()
+	tests := []struct {
+		Age      int      `validate:"expr=Age >= 18 || Guardian != \"\""`
+		Guardian string   `validate:"expr=len(Guardian) <= 4"`
+		Items    []string `validate:"expr=len(Items) <= Max && (Address == nil || Address.City != 'x;y')"`
+		Max      int
+		Address  *exprAddress
+		expected bool
+	}{
+		{20, "", nil, 2, nil, true},
+		{10, "mom", []string{"a", "b"}, 2, &exprAddress{"sh"}, true},
+		{10, "", nil, 2, nil, false},
+		{10, "grandpa", nil, 2, nil, false},
+		{20, "", []string{"a", "b", "c"}, 2, nil, false},
+		{20, "", nil, 2, &exprAddress{"x;y"}, false},
+	}
+	for _, test := range tests {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected expr,value %v %v %v,err %v", test.Age, test.Guardian, test.Items, err)
+		}
+	}
+
+	for _, tag := range []string{"Age >", "Unknown == 1", "Age == \"a", "Age @ 1"} {
+		err := validator.validateRule(reflect.ValueOf(struct{ Age int }{1}), reflect.TypeOf(1), reflect.ValueOf(1), "Age", "expr="+tag)
+		if len(err) != 1 {
+			t.Errorf("Expected expr error,expr %v,err %v", tag, err)
+			continue
+		}
+		if _, ok := err[0].(*TagError); !ok {
+			t.Errorf("Expected tag error,expr %v,err %#v", tag, err[0])
+		}
+	}
+
+	// 嵌入的 nil 指针按 nil 处理
+	testEmbed := []struct {
+		*exprInner
+		Inner    *exprInner
+		Count    int `validate:"expr=X == nil || X > Count"`
+		Total    int `validate:"expr=Inner.X == nil || Inner.X > Total"`
+		expected bool
+	}{
+		{nil, nil, 1, 1, true},
+		{&exprInner{2}, &exprInner{2}, 1, 1, true},
+		{&exprInner{1}, nil, 1, 1, false},
+		{nil, &exprInner{1}, 1, 1, false},
+	}
+	for _, test := range testEmbed {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected expr embed,value %v %v,err %v", test.exprInner, test.Inner, err)
+		}
+	}
+
+	// map 中的字段不支持表达式
+	err := validator.ValidateMap(map[string]interface{}{"age": 1}, map[string]string{"age": "expr=age > 0"})
+	if len(err) != 1 || err[0].Error() != fmt.Sprintf(trans(ValidExprStruct), "age > 0") {
+		t.Errorf("Expected expr struct error,err %v", err)
+	}
+}
+
+type thirdPartyOrder struct {
+	Name  string `validate:"len=100,200"`
+	Age   int
+	Items []thirdPartyItem
+}
+
+type thirdPartyItem struct {
+	Sku string
+}
+
+func TestStructRules(t *testing.T) {
+	validator := New().
+		RegisterStructRules(thirdPartyOrder{}, StructRules{}.Field("Name", "required;len=1,5").Field("Age", "gte=10")).
+		RegisterStructRules(&thirdPartyItem{}, StructRules{"Sku": "required"})
+	tests := []struct {
+		param    thirdPartyOrder
+		expected bool
+	}{
+		{thirdPartyOrder{"abc", 12, []thirdPartyItem{{"s1"}}}, true},
+		{thirdPartyOrder{"abcdef", 12, nil}, false},
+		{thirdPartyOrder{"abc", 9, nil}, false},
+		{thirdPartyOrder{"abc", 12, []thirdPartyItem{{""}}}, false},
+	}
+	for _, test := range tests {
+		err := validator.Struct(test.param)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected struct rules,value %v,err %v", test.param, err)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for unknown field")
+		}
+	}()
+	validator.RegisterStructRules(thirdPartyItem{}, StructRules{"Unknown": "required"})
+}
+
+type configOrder struct {
+	Quantity int `validate:"gte=1"`
+	Address  configAddress
+}
+
+type configAddress struct {
+	City string
+}
+
+type configCustomer struct {
+	Address configAddress
+}
+
+func TestRuleConfig(t *testing.T) {
+	validator := New().RegisterRuleTypes(configOrder{}, configAddress{})
+	file, err := ioutil.TempFile("", "rules*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`{"rules": {"configOrder": {"Quantity": "lte=100", "Address.City": "required"}}}`)
+	file.Close()
+	if err = validator.LoadRuleFile(file.Name()); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		param    configOrder
+		expected bool
+	}{
+		{configOrder{10, configAddress{"sh"}}, true},
+		{configOrder{0, configAddress{"sh"}}, false},
+		{configOrder{101, configAddress{"sh"}}, false},
+		{configOrder{10, configAddress{""}}, false},
+	}
+	for _, test := range tests {
+		errs := validator.Struct(test.param)
+		if (errs != nil && test.expected == true) || (errs == nil && test.expected != true) {
+			t.Errorf("Expected rule config,value %v,err %v", test.param, errs)
+		}
+	}
+
+	// override 代替 tag 规则，重新加载后立即生效
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			validator.Struct(configOrder{10, configAddress{"sh"}})
+		}
+		done <- true
+	}()
+	if err = validator.LoadRules([]byte(`{"mode": "override", "rules": {"validators.configOrder": {"Quantity": "lte=5"}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	if errs := validator.Struct(configOrder{0, configAddress{""}}); errs != nil {
+		t.Errorf("Expected override rules,err %v", errs)
+	}
+	if errs := validator.Struct(configOrder{10, configAddress{""}}); errs == nil {
+		t.Errorf("Expected reloaded lte=5 to fail")
+	}
+
+	for _, config := range []string{
+		`{"mode": "replace"}`,
+		`{"rules": {"Unknown": {"Quantity": "required"}}}`,
+		`{"rules": {"configOrder": {"Address.Street": "required"}}}`,
+		`{"rules": {"configOrder": {"Quantity": "(required"}}}`,
+		`{"rules": {"configOrder": {"Quantity": "nosuch"}}}`,
+		`{"rules": {"configOrder": {"Quantity": "lte=abc"}}}`,
+	} {
+		if err = validator.LoadRules([]byte(config)); err == nil {
+			t.Errorf("Expected rule config error,config %v", config)
+		}
+	}
+	if errs := validator.Struct(configOrder{10, configAddress{""}}); errs == nil {
+		t.Errorf("Expected failed reload to keep previous rules")
+	}
+
+	// 按路径配置的规则只作用于从该结构体开始的路径，同一字段的规则依次生效
+	validator.RegisterRuleTypes(configCustomer{})
+	if err = validator.LoadRules([]byte(`{"rules": {"configOrder": {"Address.City": "required"}, "configAddress": {"City": "len=_,5"}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	testPath := []struct {
+		param    interface{}
+		expected bool
+	}{
+		{configOrder{10, configAddress{"sh"}}, true},
+		{configOrder{10, configAddress{""}}, false},
+		{configOrder{10, configAddress{"shanghai"}}, false},
+		{configCustomer{configAddress{""}}, true},
+		{configCustomer{configAddress{"shanghai"}}, false},
+	}
+	for _, test := range testPath {
+		errs := validator.Struct(test.param)
+		if (errs != nil && test.expected == true) || (errs == nil && test.expected != true) {
+			t.Errorf("Expected rule config path,value %v,err %v", test.param, errs)
+		}
+	}
+}
+
+func TestValidateMap(t *testing.T) {
+	validator := New()
+	rules := map[string]string{
+		"name":         "required;len=1,5",
+		"age":          "gte=18",
+		"address.city": "required",
+		"items.*.sku":  "required;startswith=SKU",
+		"tags":         "unique",
+	}
+	tests := []struct {
+		param string
+		paths []string
+	}{
+		{`{"name": "bob", "age": 20, "address": {"city": "sh"}, "items": [{"sku": "SKU1"}], "tags": ["a", "b"]}`, nil},
+		{`{"name": "bob", "address": {"city": "sh"}}`, nil},
+		{`{"name": "robert", "address": {}}`, []string{"address.city", "name"}},
+		{`{"name": "bob", "age": 10, "address": {"city": "sh"}}`, []string{"age"}},
+		{`{"name": "bob", "address": {"city": "sh"}, "items": [{"sku": "SKU1"}, {"sku": "X"}, {}]}`, []string{"items.1.sku", "items.2.sku"}},
+		{`{"name": "bob", "address": {"city": "sh"}, "tags": ["a", "a"]}`, []string{"tags"}},
+	}
+	for _, test := range tests {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(test.param), &data); err != nil {
+			t.Fatal(err)
+		}
+		var paths []string
+		for _, err := range validator.ValidateMap(data, rules) {
+			fe, ok := err.(*FieldError)
+			if !ok {
+				t.Errorf("Expected field error,value %v,err %#v", test.param, err)
+				continue
+			}
+			paths = append(paths, fe.Path)
+		}
+		if !reflect.DeepEqual(paths, test.paths) {
+			t.Errorf("Expected map paths %v,value %v,got %v", test.paths, test.param, paths)
+		}
+	}
+
+	// 值的类型不适用于规则时返回错误，不会 panic
+	data := map[string]interface{}{"flag": true, "mail": "a@b.cn", "tags": 1.5}
+	errs := validator.ValidateMap(data, map[string]string{"flag": "gt=1", "mail": "email", "tags": "unique"})
+	var paths []string
+	for _, err := range errs {
+		if fe, ok := err.(*FieldError); ok {
+			paths = append(paths, fe.Path)
+		}
+	}
+	if !reflect.DeepEqual(paths, []string{"flag", "tags"}) {
+		t.Errorf("Expected kind errors for [flag tags],got %v", errs)
+	}
+}
+
+func TestErrorPath(t *testing.T) {
+	validator := New()
+	errs := validator.Struct(struct {
+		Name  string `validate:"len=1,3"`
+		Items []uniqItem
+		Inner struct {
+			Code string `validate:"number"`
+		}
+	}{Name: "abcd", Items: []uniqItem{{1, "a"}}, Inner: struct {
+		Code string `validate:"number"`
+	}{"x"}})
+	var paths []string
+	for _, err := range errs {
+		if fe, ok := err.(*FieldError); ok {
+			paths = append(paths, fe.Path)
+		}
+	}
+	if !reflect.DeepEqual(paths, []string{"Name", "Inner.Code"}) {
+		t.Errorf("Expected struct paths [Name Inner.Code],got %v,err %v", paths, errs)
+	}
+}
+
+type schemaAddress struct {
+	City string `json:"city" validate:"required;len=1,20" title:"城市"`
+}
+
+type schemaUser struct {
+	ID      string        `json:"id" validate:"required;uuid"`
+	Name    string        `json:"name" validate:"required;len=2,10;regex=/^[a-z]+$/"`
+	Age     int           `json:"age,omitempty" validate:"gte=18;lt=150"`
+	Email   string        `json:"email" validate:"email"`
+	Role    string        `json:"role" validate:"in=admin,user"`
+	Tags    []string      `json:"tags" validate:"len=_,3;unique;in=a,b"`
+	Address schemaAddress `json:"address"`
+	Friends []*schemaUser `json:"friends"`
+	Secret  string        `json:"-" validate:"required"`
+	private string
+
+	Nickname sql.NullString `json:"nickname" validate:"len=_,8"`
+	Score    sql.NullInt64  `json:"score"`
+	Balance  amount         `json:"balance"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := New().MarshalJSONSchema(schemaUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema map[string]interface{}
+	json.Unmarshal(data, &schema)
+	props := schema["properties"].(map[string]interface{})
+	expected := map[string]string{
+		"$schema":                                  JSONSchemaDraft,
+		"required":                                 "[id name]",
+		"properties.id.format":                     "uuid",
+		"properties.name.pattern":                  "^[a-z]+$",
+		"properties.name.maxLength":                "10",
+		"properties.age.minimum":                   "18",
+		"properties.age.exclusiveMaximum":          "150",
+		"properties.email.format":                  "email",
+		"properties.role.enum":                     "[admin user]",
+		"properties.tags.maxItems":                 "3",
+		"properties.tags.uniqueItems":              "true",
+		"properties.tags.items.enum":               "[a b]",
+		"properties.address.required":              "[city]",
+		"properties.address.properties.city.title": "城市",
+		"properties.friends.items.type":            "object",
+		"properties.nickname.type":                 "string",
+		"properties.nickname.maxLength":            "8",
+		"properties.score.type":                    "integer",
+		"properties.balance.type":                  "string",
+	}
+	for path, want := range expected {
+		var cur interface{} = schema
+		for _, key := range strings.Split(path, ".") {
+			if key == "$schema" {
+				cur = schema[key]
+				break
+			}
+			cur = cur.(map[string]interface{})[key]
+		}
+		if got := fmt.Sprint(cur); got != want {
+			t.Errorf("Expected schema %v = %v,got %v", path, want, got)
+		}
+	}
+	if _, ok := props["Secret"]; ok {
+		t.Errorf("Expected json:\"-\" field to be skipped")
+	}
+	if _, ok := props["private"]; ok {
+		t.Errorf("Expected unexported field to be skipped")
+	}
+}
+
+func TestJSONSchemaValidate(t *testing.T) {
+	validator := New()
+	rules, err := validator.CompileJSONSchema([]byte(`{
+		"type": "object",
+		"required": ["id", "name", "items"],
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"name": {"type": "string", "minLength": 1, "maxLength": 5, "pattern": "^[a-z;]+$"},
+			"status": {"enum": ["new", "paid", "a,b"]},
+			"amount": {"minimum": 0, "exclusiveMaximum": 100},
+			"ip": {"type": "string", "format": "ipv4"},
+			"items": {
+				"type": "array",
+				"minItems": 1,
+				"uniqueItems": true,
+				"items": {"type": "object", "required": ["sku"], "properties": {"sku": {"type": "string"}, "qty": {"type": "integer"}}}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		param string
+		paths []string
+	}{
+		{`{"id": "123e4567-e89b-12d3-a456-426614174000", "name": "ab;c", "status": "a,b", "amount": 10, "ip": "10.0.0.1", "items": [{"sku": "", "qty": 2}]}`, nil},
+		{`{"id": "123e4567-e89b-12d3-a456-426614174000", "name": "abc", "amount": "many", "items": [{"sku": "x"}]}`, nil},
+		{`{"id": "x", "name": "abcdef", "items": []}`, []string{"id", "items", "name"}},
+		{`{"id": "123e4567-e89b-12d3-a456-426614174000", "name": "abc", "status": "old", "amount": 100, "ip": "::1", "items": [{"qty": 1.5}]}`, []string{"amount", "ip", "items.0.qty", "items.0.sku", "status"}},
+		{`[1, 2]`, []string{""}},
+	}
+	for _, test := range tests {
+		var paths []string
+		for _, err := range validator.ValidateJSON([]byte(test.param), rules) {
+			fe, ok := err.(*FieldError)
+			if !ok {
+				t.Errorf("Expected field error,value %v,err %#v", test.param, err)
+				continue
+			}
+			paths = append(paths, fe.Path)
+		}
+		if !reflect.DeepEqual(paths, test.paths) {
+			t.Errorf("Expected schema paths %v,value %v,got %v", test.paths, test.param, paths)
+		}
+	}
+
+	// type 为数组，数字按 json.Number 精确比较
+	rules, err = validator.CompileJSONSchema([]byte(`{
+		"required": ["id"],
+		"properties": {
+			"id": {"type": "integer", "maximum": 9007199254740992},
+			"note": {"type": ["string", "null"], "maxLength": 3},
+			"size": {"type": ["string", "number"], "minimum": 1}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	strict := New().SetAllowEmpty(false)
+	tests = []struct {
+		param string
+		paths []string
+	}{
+		{`{"id": 9007199254740992, "note": null, "size": "x"}`, nil},
+		{`{"id": 9007199254740993, "note": "abcd", "size": 0.5}`, []string{"id", "note", "size"}},
+		{`{"note": 1, "size": true}`, []string{"id", "note", "size"}},
+	}
+	for _, test := range tests {
+		var paths []string
+		for _, err := range strict.ValidateJSON([]byte(test.param), rules) {
+			if fe, ok := err.(*FieldError); ok {
+				paths = append(paths, fe.Path)
+			}
+		}
+		if !reflect.DeepEqual(paths, test.paths) {
+			t.Errorf("Expected schema paths %v,value %v,got %v", test.paths, test.param, paths)
+		}
+	}
+	if errs := strict.ValidateJSON([]byte(`{"id": 1} x`), rules); len(errs) != 1 {
+		t.Errorf("Expected trailing data error,got %v", errs)
+	}
+
+	if _, err = validator.CompileJSONSchema([]byte(`{"type": ["string", "date"]}`)); err == nil {
+		t.Errorf("Expected unsupported type error")
+	}
+
+	// pattern 中的 "/" 不作为内联正则的结尾，无法编译的 pattern 在编译 schema 时返回错误
+	rules, err = validator.CompileJSONSchema([]byte(`{"properties": {"path": {"type": "string", "pattern": "^a/|b\\/$"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for doc, expected := range map[string]bool{`{"path": "a/x"}`: true, `{"path": "b/"}`: true, `{"path": "c"}`: false} {
+		if errs := validator.ValidateJSON([]byte(doc), rules); (len(errs) == 0) != expected {
+			t.Errorf("Expected pattern %v,value %v,err %v", expected, doc, errs)
+		}
+	}
+	if _, err = validator.CompileJSONSchema([]byte(`{"properties": {"path": {"pattern": "^(a"}}}`)); err == nil {
+		t.Errorf("Expected invalid pattern error")
+	}
+	// enum 按 JSON 类型比较，数字按数值比较
+	rules, err = validator.CompileJSONSchema([]byte(`{"properties": {"level": {"enum": [1, 2, "a,b", true]}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for doc, expected := range map[string]bool{
+		`{"level": 1.0}`:   true,
+		`{"level": 2}`:     true,
+		`{"level": "a,b"}`: true,
+		`{"level": true}`:  true,
+		`{"level": "1"}`:   false,
+		`{"level": 3}`:     false,
+		`{"level": "a"}`:   false,
+		`{"level": false}`: false,
+	} {
+		if errs := validator.ValidateJSON([]byte(doc), rules); (len(errs) == 0) != expected {
+			t.Errorf("Expected enum %v,value %v,err %v", expected, doc, errs)
+		}
+	}
+}
+
+type openAPIQuery struct {
+	OrgID  string `path:"org_id" validate:"uuid" title:"组织"`
+	Page   int    `query:"page" validate:"between=1,100" title:"页码"`
+	Status string `query:"status,omitempty" validate:"in=new,paid"`
+	Token  string `header:"X-Token" validate:"required"`
+}
+
+type openAPIRequest struct {
+	openAPIQuery
+	Name  string `json:"name" validate:"required;len=2,10;lowercase" title:"名称"`
+	Price int    `json:"price" validate:"gte=0"`
+}
+
+func TestOpenAPI(t *testing.T) {
+	validator := New()
+	params, err := validator.OpenAPIParameters(&openAPIRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(params)
+	expected := `[{"name":"org_id","in":"path","description":"组织","required":true,"schema":{"type":"string","format":"uuid","x-error-messages":{"uuid":"组织不是有效的UUID"}}},` +
+		`{"name":"page","in":"query","description":"页码","schema":{"type":"integer","minimum":1,"maximum":100,"x-error-messages":{"between":"页码必须在1到100之间"}}},` +
+		`{"name":"status","in":"query","schema":{"type":"string","enum":["new","paid"],"x-error-messages":{"in":"Status不在指定范围:[new paid]"}}},` +
+		`{"name":"X-Token","in":"header","required":true,"schema":{"type":"string","x-error-messages":{"required":"Token不能为空"}}}]`
+	if string(data) != expected {
+		t.Errorf("Expected parameters %s,got %s", expected, data)
+	}
+
+	components, err := validator.OpenAPIComponents(openAPIRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := json.Marshal(components)
+	again, _ := validator.OpenAPIComponents(openAPIRequest{})
+	second, _ := json.Marshal(again)
+	if string(first) != string(second) {
+		t.Errorf("Expected deterministic output,got %s and %s", first, second)
+	}
+	schema := components["openAPIRequest"]
+	if schema == nil {
+		t.Fatalf("Expected component openAPIRequest,got %s", first)
+	}
+	if len(schema.Properties) != 2 || fmt.Sprint(schema.Required) != "[name]" {
+		t.Errorf("Expected only body fields,got %s", first)
+	}
+	if msg := schema.Properties["price"].ErrorMessages["gte"]; msg != "Price不能小于0" {
+		t.Errorf("Expected gte message,got %s", first)
+	}
+	if msg := schema.Properties["name"].ErrorMessages["lowercase"]; !strings.HasPrefix(msg, "名称") {
+		t.Errorf("Expected lowercase message with title,got %s", first)
+	}
+	// 字符串的长度与数值使用不同的信息
+	if msg := schema.Properties["name"].ErrorMessages["len"]; msg != "名称的长度必须在2到10之间" {
+		t.Errorf("Expected string length message,got %s", first)
+	}
+	for _, test := range []struct {
+		rule string
+		prop *Schema
+		want string
+	}{
+		{"max", &Schema{Type: "string"}, "名称的长度不能大于5"},
+		{"max", &Schema{Type: "array"}, "名称的长度不能大于5"},
+		{"max", &Schema{Type: "integer"}, "名称不能大于5"},
+		{"lt", &Schema{Type: "string", Format: "date-time"}, "名称必须早于5"},
+	} {
+		if msg, _ := ruleMessage(test.rule, "名称", []string{"5"}, test.prop); msg != test.want {
+			t.Errorf("Expected %s message %s,got %s", test.rule, test.want, msg)
+		}
+	}
+
+	// 文档中的信息与校验时返回的信息一致
+	errs := validator.Struct(struct {
+		Name  string `validate:"required;len=2,10" title:"名称"`
+		Mail  string `validate:"email" title:"邮箱"`
+		Phone string `validate:"phone" title:"手机"`
+		ID    string `validate:"uuid" title:"编号"`
+		Tags  []int  `validate:"max=1" title:"标签"`
+		Count int    `validate:"len=_,5" title:"数量"`
+		Code  string `validate:"required" title:"代码"`
+	}{"a", "x", "1", "x", []int{1, 2}, 6, ""})
+	wants := []string{
+		"名称的长度必须在2到10之间",
+		"邮箱不是有效的Email",
+		"手机不是有效的手机号码",
+		"编号不是有效的UUID",
+		"标签的长度不能大于1",
+		"数量不能大于5",
+		"代码不能为空",
+	}
+	rules := []struct {
+		rule   string
+		params []string
+		prop   *Schema
+		title  string
+	}{
+		{"len", []string{"2", "10"}, &Schema{Type: "string"}, "名称"},
+		{"email", nil, &Schema{Type: "string"}, "邮箱"},
+		{"phone", nil, &Schema{Type: "string"}, "手机"},
+		{"uuid", nil, &Schema{Type: "string"}, "编号"},
+		{"max", []string{"1"}, &Schema{Type: "array"}, "标签"},
+		{"len", []string{"_", "5"}, &Schema{Type: "integer"}, "数量"},
+		{"required", nil, &Schema{Type: "string"}, "代码"},
+	}
+	if len(errs) != len(wants) {
+		t.Fatalf("Expected %d errors,got %v", len(wants), errs)
+	}
+	for i, r := range rules {
+		msg, _ := ruleMessage(r.rule, r.title, r.params, r.prop)
+		if errs[i].Error() != wants[i] || msg != wants[i] {
+			t.Errorf("Expected %s message %s,got runtime %s,openapi %s", r.rule, wants[i], errs[i], msg)
+		}
+	}
+
+	validator.SetLang("en")
+	defer validator.SetLang("zh")
+	components, _ = validator.OpenAPIComponents(openAPIRequest{})
+	if msg := components["openAPIRequest"].Properties["price"].ErrorMessages["gte"]; msg != "Price must not be less than 0" {
+		t.Errorf("Expected english message,got %s", msg)
+	}
+}
+
+func TestCheckTag(t *testing.T) {
+	validator := New()
+	testSlice := []struct {
+		tag      string
+		class    TypeClass
+		expected int
+	}{
+		{"required;len=1,5", TypeString, 0},
+		{"requried;len=1,5", TypeString, 1},
+		{"len=a,5", TypeString, 1},
+		{"max=1,2", TypeNumber, 1},
+		{"min=abc", TypeNumber, 1},
+		{"gte=now-18y", TypeTime, 0},
+		{"gte=now-18y", TypeNumber, 1},
+		{"unique", TypeString, 1},
+		{"unique", TypeList, 0},
+		{"email|phone=CN", TypeString, 0},
+		{"!in=admin", TypeAny, 0},
+		{"(email", TypeString, 1},
+		{"regex=nosuch", TypeString, 0},
+		{"regex=/^[a-z]+$/", TypeString, 0},
+		{"regex=/^(a/", TypeString, 1},
+		{"phone=ZZ", TypeString, 0},
+		{"credit_card=foo", TypeString, 1},
+		{"credit_card=visa", TypeString, 0},
+		{"duration=1s,abc", TypeAny, 1},
+		{"duration=1s,_", TypeAny, 0},
+		{"weekday=funday", TypeTime, 1},
+		{"password=bogus", TypeString, 1},
+		{"password=min:8", TypeString, 0},
+	}
+	for _, test := range testSlice {
+		errs := validator.CheckTag(test.tag, test.class)
+		if len(errs) != test.expected {
+			t.Errorf("Expected %v %v problems %v,got %v", test.tag, test.class, test.expected, errs)
+		}
+		for _, err := range errs {
+			if _, ok := err.(*TagError); !ok {
+				t.Errorf("Expected TagError,got %T", err)
+			}
+		}
+	}
+	if class := validator.TypeClassOf(reflect.TypeOf(time.Time{})); class != TypeTime {
+		t.Errorf("Expected time class,got %v", class)
+	}
+	if class := validator.TypeClassOf(reflect.TypeOf(sql.NullString{})); class != TypeAny {
+		t.Errorf("Expected custom type to be any,got %v", class)
+	}
+}
+
+func TestRuleSteps(t *testing.T) {
+	testSlice := []struct {
+		rule     string
+		expected []RuleStep
+		ok       bool
+	}{
+		{"required;len=1,5", []RuleStep{{"required", nil}, {"len", []string{"1", "5"}}}, true},
+		{"(in=a,b)", []RuleStep{{"in", []string{"a", "b"}}}, true},
+		{`regex=/^a\;b$/`, []RuleStep{{"regex", []string{"/^a;b$/"}}}, true},
+		{"email|phone", nil, false},
+		{"required;!in=a", nil, false},
+		{"(len=1", nil, false},
+	}
+	for _, test := range testSlice {
+		steps, ok := RuleSteps(test.rule)
+		if ok != test.ok || !reflect.DeepEqual(steps, test.expected) {
+			t.Errorf("Expected %v steps %v %v,got %v %v", test.rule, test.expected, test.ok, steps, ok)
+		}
+	}
+}
+
+type compileItem struct {
+	Sku   string `validate:"required;len=1,x"`
+	Count int    `validate:"expr=Count <= Max"`
+}
+
+type compileOrder struct {
+	Name    string         `validate:"requried"`
+	Tags    string         `validate:"unique"`
+	Created time.Time      `validate:"gte=now-18y"`
+	Note    sql.NullString `validate:"required;len=1,5"`
+	Items   []compileItem
+	Parent  *compileOrder
+	Ref     *compileRef
+}
+
+// compileRef 只通过指针字段引用，校验时不会展开
+type compileRef struct {
+	Code string `validate:"requried"`
+}
+
+func TestCompile(t *testing.T) {
+	validator := New()
+	errs := validator.Compile(&compileOrder{})
+	expected := []string{
+		"compileOrder.Name",
+		"compileOrder.Tags",
+		"compileOrder.Items.Sku",
+		"compileOrder.Items.Count",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d problems,got %v", len(expected), errs)
+	}
+	for i, err := range errs {
+		te, ok := err.(*TagError)
+		if !ok || te.Path != expected[i] || !strings.HasPrefix(te.Error(), expected[i]+": ") {
+			t.Errorf("Expected TagError at %v,got %#v", expected[i], err)
+		}
+	}
+	if errs := validator.Compile(schemaUser{}, exprAddress{}); len(errs) != 0 {
+		t.Errorf("Expected no problems,got %v", errs)
+	}
+
+	// 正则名称及手机号码地区按注册的内容检查
+	registered := struct {
+		Code  string `validate:"regex=orderNo"`
+		Phone string `validate:"phone=XQ"`
+	}{}
+	if errs := validator.Compile(registered); len(errs) != 2 {
+		t.Errorf("Expected unregistered pattern and region,got %v", errs)
+	}
+	RegisterPhoneRegion("XQ", "998", `[5-9]\d{7}`)
+	validator.MustRegisterPattern("orderNo", `^NO\d+$`)
+	if errs := validator.Compile(registered); len(errs) != 0 {
+		t.Errorf("Expected registered pattern and region,got %v", errs)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected MustCompile to panic")
+		}
+	}()
+	validator.MustCompile(compileOrder{})
+}
+
+func TestIP(t *testing.T) {
+	validator := New()
+	tests := []struct {
+		V4       string `validate:"ipv4"`
+		V6       string `validate:"ipv6"`
+		expected bool
+	}{
+		{"192.168.1.1", "2001:db8::1", true},
+		{"2001:db8::1", "2001:db8::1", false},
+		{"192.168.1.1", "192.168.1.1", false},
+		{"256.1.1.1", "::1", false},
+	}
+	for _, test := range tests {
+		err := validator.Struct(test)
+		if (err != nil && test.expected == true) || (err == nil && test.expected != true) {
+			t.Errorf("Expected ip,value %v %v,err %v", test.V4, test.V6, err)
+		}
+	}
+}
@@ -1,10 +1,15 @@
 package validators
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -13,27 +18,75 @@ const (
 	VALIDATOR_RANGE_SPLIT   = ","
 	VALIDATOR_IGNORE_SIGN   = "_"
 	VALIDATOR_MUTIPLE_SPLIT = ";"
+	VALIDATOR_ESCAPE_SIGN   = "\\"
+	VALIDATOR_PATH_SPLIT    = "."
 )
 
 var errorMsg map[string][]string
 var lang = "zh"
 
 type Validator struct {
-	ValidTag   string
-	TitleTag   string
-	lazy       bool
-	allowEmpty bool
-	validator  map[string]FuncCtx
+	ValidTag        string
+	TitleTag        string
+	lazy            bool
+	allowEmpty      bool
+	validator       map[string]FuncCtx
+	structValidator map[string]StructFuncCtx
+	customTypeFuncs map[reflect.Type]CustomTypeFunc
+	now             func() time.Time
+	bannedPasswords BannedPasswords
+	mu              sync.RWMutex // 保护 patterns、aliases、structRules、enums，注册可能与校验并发执行
+	patterns        map[string]*regexp.Regexp
+	inlinePatterns  sync.Map
+	enums           map[string][]string
+	rules           sync.Map // 编译后的规则树，键为规则原文
+	aliases         map[string]string
+	aliasDetail     bool
+	exprs           sync.Map // 按结构体类型编译的表达式
+	structRules     map[reflect.Type]StructRules
+	ruleTypes       map[string]reflect.Type
+	ruleSet         atomic.Value // 从配置加载的规则 *ruleSet
 }
 
+// New 创建校验器
+// 每个校验器持有内置规则的副本，RegisterValidator 等注册的规则只对当前校验器生效，不再影响其他 New 创建的校验器
+// password、regex、in 等规则依赖校验器自身的配置，因此不能与其他校验器共享规则表
 func New() *Validator {
-	return &Validator{
+	v := &Validator{
 		ValidTag:   "validate",
 		TitleTag:   "title",
 		lazy:       true,
 		allowEmpty: true,
-		validator:  defaultValidator,
+		validator:  make(map[string]FuncCtx, len(defaultValidator)),
+		now:        time.Now,
+		customTypeFuncs: map[reflect.Type]CustomTypeFunc{
+			reflect.TypeOf(sql.NullString{}):  valuerTypeFunc,
+			reflect.TypeOf(sql.NullInt64{}):   valuerTypeFunc,
+			reflect.TypeOf(sql.NullFloat64{}): valuerTypeFunc,
+			reflect.TypeOf(sql.NullBool{}):    valuerTypeFunc,
+		},
+		patterns:    make(map[string]*regexp.Regexp),
+		enums:       make(map[string][]string),
+		aliases:     make(map[string]string),
+		structRules: make(map[reflect.Type]StructRules),
+		ruleTypes:   make(map[string]reflect.Type),
 	}
+	for validatorK, validatorV := range defaultValidator {
+		v.validator[validatorK] = validatorV
+	}
+	// 依赖 Validator 配置的规则
+	v.validator["password"] = v.isPassword
+	v.validator["regex"] = v.isRegex
+	v.validator["in"] = v.isIn
+	for ruler, op := range timeCompareRules {
+		v.validator[ruler] = v.timeRule(op, v.validator[ruler])
+	}
+	v.structValidator = map[string]StructFuncCtx{
+		"after":  v.isAfter,
+		"before": v.isBefore,
+		"expr":   v.isExpr,
+	}
+	return v
 }
 
 // SetValidTag 设置校验tag
@@ -66,7 +119,7 @@ func (v *Validator) SetLazy(lazy bool) *Validator {
 	return v
 }
 
-// RegisterValidator 注册新验证规则
+// RegisterValidator 注册新验证规则，只对当前校验器生效
 func (v *Validator) RegisterValidator(validatorK string, validator FuncCtx) *Validator {
 	v.validator[validatorK] = validator
 	return v
@@ -80,11 +133,107 @@ func (v *Validator) RegisterValidators(validatorMap map[string]FuncCtx) *Validat
 	return v
 }
 
+// RegisterStructValidator 注册可访问所在结构体的验证规则
+func (v *Validator) RegisterStructValidator(validatorK string, validator StructFuncCtx) *Validator {
+	v.structValidator[validatorK] = validator
+	return v
+}
+
+// RegisterCustomTypeFunc 注册自定义类型转换，字段在执行校验规则前先由 fn 转为基础类型的值
+func (v *Validator) RegisterCustomTypeFunc(fn CustomTypeFunc, types ...interface{}) *Validator {
+	for _, t := range types {
+		v.customTypeFuncs[reflect.TypeOf(t)] = fn
+	}
+	return v
+}
+
+// StructRules 字段名到校验规则的映射，用于无法添加 tag 的结构体
+//
+//	validators.StructRules{}.Field("Name", "required;len=1,5").Field("Age", "gte=10")
+type StructRules map[string]string
+
+// Field 设置字段的校验规则
+func (r StructRules) Field(name string, rules string) StructRules {
+	r[name] = rules
+	return r
+}
+
+// RegisterStructRules 为结构体注册校验规则，代替该类型字段上的校验 tag，字段不存在时 panic
+func (v *Validator) RegisterStructRules(s interface{}, rules StructRules) *Validator {
+	rt := reflect.TypeOf(s)
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	for name := range rules {
+		if _, ok := rt.FieldByName(name); !ok {
+			panic(fmt.Sprintf("结构体 %v 不存在字段 %s", rt, name))
+		}
+	}
+	v.mu.Lock()
+	v.structRules[rt] = rules
+	v.mu.Unlock()
+	return v
+}
+
+// RegisterAlias 注册规则别名，如 RegisterAlias("username", "required;len=3,32;lowercase")
+// 别名在编译 tag 时展开，校验失败时错误信息使用别名，FieldError.Checks 为未通过的规则
+func (v *Validator) RegisterAlias(alias string, rules string) *Validator {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.aliases[alias] = rules
+	// 已编译的规则可能引用了该别名
+	v.rules.Range(func(key, value interface{}) bool {
+		v.rules.Delete(key)
+		return true
+	})
+	return v
+}
+
+// SetAliasDetail 别名校验失败时，错误信息中附带未通过规则的错误信息
+func (v *Validator) SetAliasDetail(detail bool) *Validator {
+	v.aliasDetail = detail
+	return v
+}
+
+// SetClock 设置时间规则使用的当前时间，便于测试
+func (v *Validator) SetClock(now func() time.Time) *Validator {
+	v.now = now
+	return v
+}
+
+// RegisterPattern 注册命名正则，供 regex=name 使用，正则只编译一次，正则有误时返回错误
+func (v *Validator) RegisterPattern(name string, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	v.mu.Lock()
+	v.patterns[name] = re
+	v.mu.Unlock()
+	return nil
+}
+
+// MustRegisterPattern 同 RegisterPattern，正则有误时 panic
+func (v *Validator) MustRegisterPattern(name string, pattern string) *Validator {
+	if err := v.RegisterPattern(name, pattern); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// pattern 查找 RegisterPattern 注册的正则
+func (v *Validator) pattern(name string) (re *regexp.Regexp, ok bool) {
+	v.mu.RLock()
+	re, ok = v.patterns[name]
+	v.mu.RUnlock()
+	return
+}
+
 // LazyValidate 延迟校验输出
 func (v *Validator) LazyValidate(s interface{}) (err error) {
 	syncMap := &sync.Map{}
-	parentKey := v.ValidTag
-	errArr := v.validate(s, true, syncMap, parentKey)
+	parentKey := ""
+	errArr := v.validate(s, true, syncMap, parentKey, nil)
 	syncMap = nil
 	if errArr != nil {
 		err = errArr[0]
@@ -95,8 +244,8 @@ func (v *Validator) LazyValidate(s interface{}) (err error) {
 // Struct 校验结构体
 func (v *Validator) Struct(s interface{}) (err []error) {
 	syncMap := &sync.Map{}
-	parentKey := v.ValidTag
-	err = v.validate(s, false, syncMap, parentKey)
+	parentKey := ""
+	err = v.validate(s, false, syncMap, parentKey, nil)
 	syncMap = nil
 	return
 }
@@ -104,13 +253,14 @@ func (v *Validator) Struct(s interface{}) (err []error) {
 // Value 校验值
 func (v *Validator) Value(s interface{}) (err []error) {
 	syncMap := &sync.Map{}
-	parentKey := v.ValidTag
-	err = v.validate(s, false, syncMap, parentKey)
+	parentKey := ""
+	err = v.validate(s, false, syncMap, parentKey, nil)
 	syncMap = nil
 	return
 }
 
-func (v *Validator) validate(s interface{}, lazyFlag bool, syncMap *sync.Map, parentKey string) (errs []error) {
+// scope 为外层结构体传入的配置规则
+func (v *Validator) validate(s interface{}, lazyFlag bool, syncMap *sync.Map, parentKey string, scope *ruleScope) (errs []error) {
 	var errArr []error
 	rt := reflect.TypeOf(s)
 	rv := reflect.ValueOf(s)
@@ -123,8 +273,8 @@ func (v *Validator) validate(s interface{}, lazyFlag bool, syncMap *sync.Map, pa
 		//判断是否需要递归
 		if ok, fieldNum := checkArrayValueIsMulti(rv); ok {
 			for i := 0; i < fieldNum; i++ {
-				tmpParentKey := fmt.Sprintf("%v_%v", parentKey, i)
-				errArr = v.validate(rv.Index(i).Interface(), lazyFlag, syncMap, tmpParentKey)
+				tmpParentKey := joinPath(parentKey, strconv.Itoa(i))
+				errArr = v.validate(rv.Index(i).Interface(), lazyFlag, syncMap, tmpParentKey, scope)
 				if len(errArr) > 0 {
 					errs = append(errs, errArr...)
 					if lazyFlag {
@@ -139,6 +289,7 @@ func (v *Validator) validate(s interface{}, lazyFlag bool, syncMap *sync.Map, pa
 		}
 		break
 	case reflect.Struct:
+		scope = v.loadedRules().scope(rt, scope)
 		numField := rv.NumField()
 		if numField <= 0 {
 			if v.allowEmpty {
@@ -152,20 +303,33 @@ func (v *Validator) validate(s interface{}, lazyFlag bool, syncMap *sync.Map, pa
 			fv := rv.Field(i)
 			ft := rt.Field(i).Type
 			fieldTypeInfo := rv.Type().Field(i)
-			fieldType := fv.Type().Kind()
-			tag := fieldTypeInfo.Tag.Get(v.ValidTag)
+			tag := v.fieldRules(scope, rt, fieldTypeInfo)
 			title := fieldTypeInfo.Tag.Get(v.TitleTag)
+			if title == "" {
+				title = fieldTypeInfo.Name
+			}
+			// 自定义类型先转为基础类型，转换后的值不再递归校验
+			custom := false
+			var valueErr error
+			if cv, ok, err := v.customValue(fv); ok {
+				fv, ft, custom, valueErr = cv, nil, true, err
+				if fv.IsValid() {
+					ft = fv.Type()
+				}
+			}
 			if tag != "" {
 				//fmt.Println("ffff:", fv, ft, fieldTypeInfo)
-				//没有配置 required，并且 field 为 0 值的，直接跳过
-				isZeroValue := isZeroValue(fv)
-				if isZeroValue && !strings.Contains(tag, "required") && !v.allowEmpty {
-					continue
-				}
-				if title == "" {
-					title = fieldTypeInfo.Name
+				if valueErr != nil {
+					errArr = []error{newFieldError(title, tag, fmt.Sprintf(trans(ValidValuer), title, valueErr))}
+				} else {
+					//没有配置 required，并且 field 为 0 值的，直接跳过
+					isZeroValue := isZeroValue(fv)
+					if isZeroValue && !v.allowEmpty && !v.checksZero(tag) {
+						continue
+					}
+					errArr = v.validateRule(rv, ft, fv, title, tag)
 				}
-				errArr = v.validateRule(ft, fv, title, tag)
+				setErrorPath(errArr, joinPath(parentKey, fieldTypeInfo.Name))
 				if len(errArr) > 0 {
 					errs = append(errs, errArr...)
 					if lazyFlag {
@@ -174,72 +338,114 @@ func (v *Validator) validate(s interface{}, lazyFlag bool, syncMap *sync.Map, pa
 					continue
 				}
 			}
-			//判断是否需要递归
-			if ok, fieldNum := checkArrayValueIsMulti(fv); ok {
-				for i := 0; i < fieldNum; i++ {
-					//tmpParentKey := fmt.Sprintf("%v_%v", parentKey, fieldTypeInfo.Name)
-					//tmpParentKey := fmt.Sprintf("%v_%v", parentKey, fieldTypeInfo.Name)
-					//fmt.Println("tmpParentKey:",fv.Index(i).Interface(),tmpParentKey)tmpParentKey
-					errArr = v.validate(fv.Index(i).Interface(), lazyFlag, syncMap, parentKey)
-					if len(errArr) > 0 {
-						errs = append(errs, errArr...)
-						if lazyFlag {
-							return
-						}
-						continue
-					}
+			if custom {
+				continue
+			}
+			errArr = v.validateChildren(fv, lazyFlag, syncMap, joinPath(parentKey, fieldTypeInfo.Name), scope.child(fieldTypeInfo.Name))
+			if len(errArr) > 0 {
+				errs = append(errs, errArr...)
+				if lazyFlag {
+					return
 				}
 			}
+		}
+	}
+	return
+}
 
-			if fieldType == reflect.Struct {
-				tmpParentKey := fmt.Sprintf("%v_%v", parentKey, fieldTypeInfo.Name)
-				errArr = v.validate(fv.Interface(), lazyFlag, syncMap, tmpParentKey)
-				if len(errArr) > 0 {
-					errs = append(errs, errArr...)
-					if lazyFlag {
-						return
-					}
-					continue
+// validateChildren 递归校验字段中的结构体及数组、map 中的结构体，scope 为字段继承的配置规则
+func (v *Validator) validateChildren(fv reflect.Value, lazyFlag bool, syncMap *sync.Map, parentKey string, scope *ruleScope) (errs []error) {
+	var errArr []error
+	//判断是否需要递归
+	if ok, fieldNum := checkArrayValueIsMulti(fv); ok {
+		var keys []reflect.Value
+		if fv.Kind() == reflect.Map {
+			keys = fv.MapKeys()
+		}
+		for i := 0; i < fieldNum; i++ {
+			var elem reflect.Value
+			tmpParentKey := parentKey
+			if keys != nil {
+				elem = fv.MapIndex(keys[i])
+				tmpParentKey = joinPath(tmpParentKey, fmt.Sprint(keys[i]))
+			} else {
+				elem = fv.Index(i)
+				tmpParentKey = joinPath(tmpParentKey, strconv.Itoa(i))
+			}
+			errArr = v.validate(elem.Interface(), lazyFlag, syncMap, tmpParentKey, scope)
+			if len(errArr) > 0 {
+				errs = append(errs, errArr...)
+				if lazyFlag {
+					return
 				}
+				continue
 			}
 		}
 	}
+
+	if fv.Kind() == reflect.Struct {
+		errs = append(errs, v.validate(fv.Interface(), lazyFlag, syncMap, parentKey, scope)...)
+	}
 	return
 }
 
-func (v *Validator) validateRule(typeObj reflect.Type, typeValue reflect.Value, title string, rulerString string) (errs []error) {
-	//typeObj := reflect.TypeOf(s)
-	//typeValue := reflect.ValueOf(s)
-	rulers := strings.Split(rulerString, VALIDATOR_MUTIPLE_SPLIT)
-	for _, ruler := range rulers {
-		var params []string
-		//查找是否含有赋值符号
-		num := strings.Index(ruler, VALIDATOR_VALUE_SIGN)
-		//不等于 -1, 表示含有"="
-		if num != -1 {
-			params = strings.Split(ruler[num+1:], VALIDATOR_RANGE_SPLIT)
-			ruler = ruler[0:num]
+func (v *Validator) validateRule(structValue reflect.Value, typeObj reflect.Type, typeValue reflect.Value, title string, rulerString string) (errs []error) {
+	node, err := v.compileRule(rulerString)
+	if err != nil {
+		return []error{err}
+	}
+	return v.evalRule(node, structValue, typeObj, typeValue, title)
+}
+
+// fieldRules 字段的校验规则，依次取 tag、RegisterStructRules 注册的规则及配置加载的规则
+func (v *Validator) fieldRules(scope *ruleScope, rt reflect.Type, sf reflect.StructField) string {
+	tag := sf.Tag.Get(v.ValidTag)
+	v.mu.RLock()
+	rules, ok := v.structRules[rt]
+	v.mu.RUnlock()
+	if ok {
+		tag = rules[sf.Name]
+	}
+	return scope.fieldRule(sf.Name, tag)
+}
+
+// joinPath 拼接字段路径，如 Items.0.Sku
+func joinPath(parent string, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + VALIDATOR_PATH_SPLIT + name
+}
+
+// setErrorPath 为未设置路径的 FieldError 设置字段路径
+func setErrorPath(errs []error, path string) {
+	for _, err := range errs {
+		if fe, ok := err.(*FieldError); ok && fe.Path == "" {
+			fe.Path = path
 		}
-		// 判断验证规则是否存在
-		if _, ok := v.validator[ruler]; !ok {
-			errs = append(errs, fmt.Errorf(trans(ValidNotExist), ruler))
-			if v.lazy == false {
-				return
-			}
+	}
+}
+
+// splitEscaped 按 sep 拆分规则，"\"+sep 表示字面量 sep，其余的"\"原样保留
+func splitEscaped(s string, sep string) []string {
+	if !strings.Contains(s, VALIDATOR_ESCAPE_SIGN+sep) {
+		return strings.Split(s, sep)
+	}
+	var parts []string
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], VALIDATOR_ESCAPE_SIGN+sep) {
+			buf.WriteString(sep)
+			i += len(VALIDATOR_ESCAPE_SIGN+sep) - 1
 			continue
 		}
-
-		// 验证规则
-		//fmt.Println("typeValue", typeValue.String())
-		err := v.validator[ruler](typeObj, typeValue, title, params...)
-		if err != nil {
-			errs = append(errs, err)
-			if v.lazy == false {
-				return
-			}
+		if strings.HasPrefix(s[i:], sep) {
+			parts = append(parts, buf.String())
+			buf.Reset()
+			i += len(sep) - 1
 			continue
 		}
-
+		buf.WriteByte(s[i])
 	}
-	return
+	return append(parts, buf.String())
 }
@@ -0,0 +1,421 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 规则的静态检查，不需要字段的值，供 cmd/validatorslint 及 Compile 使用
+
+// TypeClass 字段类型的分类，用于检查规则是否适用于字段
+type TypeClass int
+
+const (
+	TypeAny      TypeClass = iota // 无法确定类型，不检查规则是否适用
+	TypeString                    // 字符串
+	TypeNumber                    // 整数、浮点数及 math/big 类型
+	TypeBool                      // 布尔
+	TypeList                      // 数组、切片
+	TypeMap                       // map
+	TypeStruct                    // 结构体
+	TypeTime                      // time.Time
+	TypeDuration                  // time.Duration
+)
+
+var typeClassNames = map[TypeClass]string{
+	TypeAny:      "any",
+	TypeString:   "string",
+	TypeNumber:   "number",
+	TypeBool:     "bool",
+	TypeList:     "array",
+	TypeMap:      "map",
+	TypeStruct:   "struct",
+	TypeTime:     "time",
+	TypeDuration: "duration",
+}
+
+func (c TypeClass) String() string {
+	return typeClassNames[c]
+}
+
+// TypeClassOf 类型的分类，指针、接口及自定义类型(如 sql.NullString)返回 TypeAny
+func (v *Validator) TypeClassOf(rt reflect.Type) TypeClass {
+	switch {
+	case rt == timeType:
+		return TypeTime
+	case rt == durationType:
+		return TypeDuration
+	case isBigType(rt):
+		return TypeNumber
+	}
+	if _, ok := v.customTypeFuncs[rt]; ok || rt.Implements(valuerType) {
+		return TypeAny
+	}
+	switch rt.Kind() {
+	case reflect.String:
+		return TypeString
+	case reflect.Bool:
+		return TypeBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return TypeNumber
+	case reflect.Slice, reflect.Array:
+		return TypeList
+	case reflect.Map:
+		return TypeMap
+	case reflect.Struct:
+		return TypeStruct
+	}
+	return TypeAny
+}
+
+// 参数的格式
+const (
+	paramAny    = iota
+	paramLength // 非负整数或 "_"
+	paramNumber // 数字或 "_"
+	paramBound  // 数字，time.Time 字段也可以是时间，如 now-18y
+)
+
+// ruleSpec 规则的参数个数、参数格式及适用的字段类型，max 为 -1 表示不限个数，types 为空表示不限类型
+type ruleSpec struct {
+	min, max int
+	param    int
+	types    []TypeClass
+}
+
+var (
+	stringTypes  = []TypeClass{TypeString}
+	lengthTypes  = []TypeClass{TypeString, TypeNumber, TypeList, TypeMap}
+	boundTypes   = []TypeClass{TypeString, TypeNumber, TypeList, TypeMap, TypeTime}
+	decimalTypes = []TypeClass{TypeString, TypeNumber}
+)
+
+// ruleSpecs 内置规则的说明，RegisterValidator 注册的规则只检查是否存在
+var ruleSpecs = map[string]ruleSpec{
+	"required":      {0, 0, paramAny, nil},
+	"present":       {0, 0, paramAny, nil},
+	"len":           {1, 2, paramLength, lengthTypes},
+	"len_runes":     {1, 2, paramLength, lengthTypes},
+	"len_bytes":     {1, 2, paramLength, lengthTypes},
+	"len_graphemes": {1, 2, paramLength, lengthTypes},
+	"min":           {1, 1, paramBound, boundTypes},
+	"max":           {1, 1, paramBound, boundTypes},
+	"lt":            {1, 1, paramBound, boundTypes},
+	"lte":           {1, 1, paramBound, boundTypes},
+	"gt":            {1, 1, paramBound, boundTypes},
+	"gte":           {1, 1, paramBound, boundTypes},
+	"eq":            {1, 1, paramAny, lengthTypes},
+	"between":       {2, 2, paramNumber, decimalTypes},
+	"multiple_of":   {1, 1, paramNumber, decimalTypes},
+	"decimal":       {2, 2, paramLength, decimalTypes},
+	"number":        {0, 0, paramAny, decimalTypes},
+	"email":         {0, 0, paramAny, stringTypes},
+	"uuid":          {0, 0, paramAny, stringTypes},
+	"phone":         {0, -1, paramAny, stringTypes},
+	"e164":          {0, 0, paramAny, stringTypes},
+	"landline":      {0, 0, paramAny, stringTypes},
+	"uscc":          {0, 0, paramAny, stringTypes},
+	"orgcode":       {0, 0, paramAny, stringTypes},
+	"taxno":         {0, 0, paramAny, stringTypes},
+	"luhn":          {0, 0, paramAny, stringTypes},
+	"bankcard":      {0, 1, paramAny, stringTypes},
+	"credit_card":   {0, -1, paramAny, stringTypes},
+	"iban":          {0, 0, paramAny, stringTypes},
+	"ipv4":          {0, 0, paramAny, stringTypes},
+	"ipv6":          {0, 0, paramAny, stringTypes},
+	"ip":            {0, 0, paramAny, stringTypes},
+	"contains":      {1, -1, paramAny, stringTypes},
+	"contains_i":    {1, -1, paramAny, stringTypes},
+	"containsany":   {1, -1, paramAny, stringTypes},
+	"containsany_i": {1, -1, paramAny, stringTypes},
+	"excludes":      {1, -1, paramAny, stringTypes},
+	"excludes_i":    {1, -1, paramAny, stringTypes},
+	"excludesall":   {1, -1, paramAny, stringTypes},
+	"startswith":    {1, -1, paramAny, stringTypes},
+	"startswith_i":  {1, -1, paramAny, stringTypes},
+	"endswith":      {1, -1, paramAny, stringTypes},
+	"endswith_i":    {1, -1, paramAny, stringTypes},
+	"lowercase":     {0, 0, paramAny, stringTypes},
+	"uppercase":     {0, 0, paramAny, stringTypes},
+	"notblank":      {0, 0, paramAny, stringTypes},
+	"regex":         {1, -1, paramAny, stringTypes},
+	"password":      {0, -1, paramAny, stringTypes},
+	"date":          {0, -1, paramAny, stringTypes},
+	"duration":      {2, 2, paramAny, []TypeClass{TypeString, TypeDuration}},
+	"weekday":       {1, -1, paramAny, []TypeClass{TypeString, TypeTime}},
+	"timeofday":     {2, 3, paramAny, []TypeClass{TypeString, TypeTime}},
+	"after":         {1, 1, paramAny, []TypeClass{TypeTime}},
+	"before":        {1, 1, paramAny, []TypeClass{TypeTime}},
+	"unique":        {0, 1, paramAny, []TypeClass{TypeList, TypeMap}},
+	"unique_i":      {0, 1, paramAny, []TypeClass{TypeList, TypeMap}},
+	"in":            {0, -1, paramAny, nil},
+	"jsontype":      {1, -1, paramAny, nil},
+	"jsonenum":      {1, -1, paramAny, nil},
+	"expr":          {1, 1, paramAny, nil},
+}
+
+// CheckTag 检查规则的语法、规则是否存在、参数个数和格式，以及规则是否适用于 class 类型的字段
+// 不检查运行时注册的正则名称及手机号码地区(见 Compile)，返回全部问题，均为 TagError
+func (v *Validator) CheckTag(tag string, class TypeClass) (errs []error) {
+	node, err := v.compileRule(tag)
+	if err != nil {
+		return []error{err}
+	}
+	return v.checkRule(node, class)
+}
+
+func (v *Validator) checkRule(node *ruleNode, class TypeClass) (errs []error) {
+	if node.kind != ruleLeaf {
+		for _, child := range node.children {
+			errs = append(errs, v.checkRule(child, class)...)
+		}
+		return
+	}
+	if name := v.missingRule(node); name != "" {
+		return []error{&TagError{Tag: node.text, Msg: fmt.Sprintf(trans(ValidNotExist), name)}}
+	}
+	spec, ok := ruleSpecs[node.name]
+	if !ok {
+		return
+	}
+	if n := len(node.params); n < spec.min || (spec.max != -1 && n > spec.max) {
+		errs = append(errs, &TagError{Tag: node.text, Msg: fmt.Sprintf(trans(ValidRuleParams), node.name, spec.count(), n)})
+	}
+	for _, param := range node.params {
+		if !spec.validParam(param, class) {
+			errs = append(errs, &TagError{Tag: node.text, Msg: fmt.Sprintf(trans(ValidRuleParam), node.name, param)})
+		}
+	}
+	if check, ok := ruleParamChecks[node.name]; ok && len(node.params) > 0 {
+		if err := check(v, node.params); err != nil {
+			errs = append(errs, &TagError{Tag: node.text, Msg: err.Error()})
+		}
+	}
+	if class != TypeAny && len(spec.types) > 0 && !spec.allows(class) {
+		errs = append(errs, &TagError{Tag: node.text, Msg: fmt.Sprintf(trans(ValidRuleKind), node.name, class)})
+	}
+	return
+}
+
+// ruleParamChecks 按校验时使用的固定表检查参数，如内联正则、卡组织、密码策略，返回与校验时相同的错误
+var ruleParamChecks = map[string]func(v *Validator, params []string) error{
+	"regex": func(v *Validator, params []string) error {
+		name := strings.Join(params, VALIDATOR_RANGE_SPLIT)
+		if !strings.HasPrefix(name, "/") {
+			return nil
+		}
+		_, err := v.compilePattern(name)
+		return err
+	},
+	"credit_card": func(v *Validator, params []string) error {
+		for _, brand := range params {
+			if _, ok := cardBrands[strings.ToLower(brand)]; !ok {
+				return fmt.Errorf(trans(ValidCardBrand), brand)
+			}
+		}
+		return nil
+	},
+	"bankcard": func(v *Validator, params []string) error {
+		for _, param := range params {
+			if param != "unionpay" {
+				return fmt.Errorf(trans(ValidCardBrand), param)
+			}
+		}
+		return nil
+	},
+	"duration": func(v *Validator, params []string) error {
+		for _, param := range params {
+			if _, err := time.ParseDuration(param); err != nil && param != VALIDATOR_IGNORE_SIGN {
+				return fmt.Errorf(trans(ValidTimeParam), param)
+			}
+		}
+		return nil
+	},
+	"weekday": func(v *Validator, params []string) error {
+		_, _, _, err := parseWeekdayParams(params)
+		return err
+	},
+	"password": func(v *Validator, params []string) error {
+		for _, param := range params {
+			if _, _, err := parsePasswordPolicy(param); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// ruleRegistryChecks 按运行时注册的内容检查参数，如 RegisterPattern 注册的正则、RegisterPhoneRegion 注册的地区
+// 只在 Compile 中检查，CheckTag 供 validatorslint 使用，无法得知运行时注册的内容
+var ruleRegistryChecks = map[string]func(v *Validator, params []string) error{
+	"regex": func(v *Validator, params []string) error {
+		name := strings.Join(params, VALIDATOR_RANGE_SPLIT)
+		if strings.HasPrefix(name, "/") {
+			return nil
+		}
+		_, err := v.compilePattern(name)
+		return err
+	},
+	"phone": func(v *Validator, params []string) error {
+		for _, region := range params {
+			if _, ok := lookupPhoneMeta(region); !ok {
+				return fmt.Errorf(trans(ValidPhoneRegion), region)
+			}
+		}
+		return nil
+	},
+}
+
+// count 参数个数的说明，如 1、1-2、1+
+func (s ruleSpec) count() string {
+	switch {
+	case s.max == -1:
+		return strconv.Itoa(s.min) + "+"
+	case s.min == s.max:
+		return strconv.Itoa(s.min)
+	}
+	return strconv.Itoa(s.min) + "-" + strconv.Itoa(s.max)
+}
+
+func (s ruleSpec) allows(class TypeClass) bool {
+	for _, t := range s.types {
+		if t == class {
+			return true
+		}
+	}
+	return false
+}
+
+func (s ruleSpec) validParam(param string, class TypeClass) bool {
+	switch s.param {
+	case paramLength:
+		return param == VALIDATOR_IGNORE_SIGN || numberRegex.MatchString(param)
+	case paramNumber:
+		return param == VALIDATOR_IGNORE_SIGN || numericRegex.MatchString(param)
+	case paramBound:
+		if numericRegex.MatchString(param) {
+			return true
+		}
+		if class == TypeTime || class == TypeAny {
+			_, err := parseTimeParam(param, time.Now())
+			return err == nil
+		}
+		return false
+	}
+	return true
+}
+
+// Compile 检查类型(递归检查字段中的结构体)上全部字段的规则，返回全部配置问题，均为设置了 Path 的 TagError
+// 规则来源与校验时一致(tag、RegisterStructRules 及 LoadRules)，编译结果会缓存，用于启动时提前发现配置错误
+func (v *Validator) Compile(types ...interface{}) (errs []error) {
+	set := v.loadedRules()
+	visited := make(map[reflect.Type]bool)
+	for _, t := range types {
+		rt := reflect.TypeOf(t)
+		for rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+		errs = append(errs, v.compileType(set, nil, rt, rt.Name(), visited)...)
+	}
+	return
+}
+
+// MustCompile 同 Compile，有配置问题时 panic
+func (v *Validator) MustCompile(types ...interface{}) *Validator {
+	if errs := v.Compile(types...); len(errs) > 0 {
+		msgs := make([]string, 0, len(errs))
+		for _, err := range errs {
+			msgs = append(msgs, err.Error())
+		}
+		panic(strings.Join(msgs, "\n"))
+	}
+	return v
+}
+
+// compileType 按校验时的遍历方式检查类型：结构体检查字段，数组、切片的元素为结构体、数组、切片或 map 时检查元素
+// 指针字段及 map 不展开，与 Struct 一致，scope 为外层结构体传入的配置规则
+func (v *Validator) compileType(set *ruleSet, scope *ruleScope, rt reflect.Type, path string, visited map[reflect.Type]bool) (errs []error) {
+	switch rt.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v.compileElem(set, scope, rt, path, visited)
+	case reflect.Struct:
+	default:
+		return
+	}
+	if rt == timeType || isBigType(rt) || visited[rt] {
+		return
+	}
+	visited[rt] = true
+	scope = set.scope(rt, scope)
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		fieldPath := joinPath(path, sf.Name)
+		if tag := v.fieldRules(scope, rt, sf); tag != "" {
+			for _, err := range v.compileField(rt, sf, tag) {
+				if te, ok := err.(*TagError); ok {
+					te.Path = fieldPath
+				}
+				errs = append(errs, err)
+			}
+		}
+		// 自定义类型转换后不再递归，其余同 validateChildren
+		if _, custom := v.customType(sf.Type); custom {
+			continue
+		}
+		switch sf.Type.Kind() {
+		case reflect.Struct:
+			errs = append(errs, v.compileType(set, scope.child(sf.Name), sf.Type, fieldPath, visited)...)
+		case reflect.Slice, reflect.Array, reflect.Map:
+			errs = append(errs, v.compileElem(set, scope.child(sf.Name), sf.Type, fieldPath, visited)...)
+		}
+	}
+	return
+}
+
+// compileElem 数组、切片、map 的元素为结构体、数组、切片或 map 时检查元素，同 checkArrayValueIsMulti
+func (v *Validator) compileElem(set *ruleSet, scope *ruleScope, rt reflect.Type, path string, visited map[reflect.Type]bool) []error {
+	switch rt.Elem().Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return v.compileType(set, scope, rt.Elem(), path, visited)
+	}
+	return nil
+}
+
+// compileField 检查字段的规则，expr 按字段所在的结构体编译，正则名称及手机号码地区按当前注册的内容检查
+func (v *Validator) compileField(rt reflect.Type, sf reflect.StructField, tag string) (errs []error) {
+	node, err := v.compileRule(tag)
+	if err != nil {
+		return []error{err}
+	}
+	errs = v.checkRule(node, v.TypeClassOf(sf.Type))
+	walkRule(node, func(leaf *ruleNode) {
+		if check, ok := ruleRegistryChecks[leaf.name]; ok && len(leaf.params) > 0 {
+			if err := check(v, leaf.params); err != nil {
+				errs = append(errs, &TagError{Tag: leaf.text, Msg: err.Error()})
+			}
+		}
+		if leaf.name != "expr" || len(leaf.params) != 1 {
+			return
+		}
+		if _, e := compileExpr(leaf.params[0], rt); e != nil {
+			errs = append(errs, &TagError{Tag: leaf.text, Msg: fmt.Sprintf(trans(ValidExprSyntax), leaf.params[0], e)})
+		}
+	})
+	return
+}
+
+// walkRule 遍历规则树的叶子节点
+func walkRule(node *ruleNode, fn func(leaf *ruleNode)) {
+	if node.kind == ruleLeaf {
+		fn(node)
+		return
+	}
+	for _, child := range node.children {
+		walkRule(child, fn)
+	}
+}
@@ -0,0 +1,224 @@
+package validators
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// 从 JSON 配置加载校验规则，无需重新部署即可调整规则
+//
+//	{
+//	  "mode": "merge",
+//	  "rules": {
+//	    "Order": {"Quantity": "max=100", "Address.City": "required"}
+//	  }
+//	}
+//
+// rules 的键为 RegisterRuleTypes 注册的结构体名(如 Order 或 main.Order)，
+// 值为字段路径到规则的映射，嵌套字段用 "." 分隔，规则只在从该结构体开始校验到对应字段时生效
+// mode 为 merge 时配置的规则追加在 tag 规则之后，为 override 时代替 tag 规则
+
+// 配置规则与 tag 规则的合并方式
+const (
+	RuleModeMerge    = "merge"
+	RuleModeOverride = "override"
+)
+
+// RuleConfig 规则配置文件的内容
+type RuleConfig struct {
+	Mode  string                       `json:"mode"`
+	Rules map[string]map[string]string `json:"rules"`
+}
+
+// ruleSet 加载后的规则，按结构体类型和字段路径索引，加载后不再修改
+type ruleSet struct {
+	mode   string
+	fields map[reflect.Type]map[string]string
+}
+
+// ruleScope 校验到某个结构体时可用的配置规则，键为相对该结构体的字段路径(如 Address.City)
+// 包含该结构体类型的规则及从外层结构体继承的规则，同一字段的多条规则依次生效
+type ruleScope struct {
+	mode  string
+	rules map[string][]string
+}
+
+// scope 从外层进入结构体 rt 时可用的配置规则，parent 为外层的 child 返回的规则
+func (s *ruleSet) scope(rt reflect.Type, parent *ruleScope) *ruleScope {
+	if s == nil {
+		return parent
+	}
+	own := s.fields[rt]
+	if len(own) == 0 {
+		return parent
+	}
+	scope := &ruleScope{mode: s.mode, rules: make(map[string][]string)}
+	for path, rule := range own {
+		scope.rules[path] = append(scope.rules[path], rule)
+	}
+	if parent != nil {
+		for path, rules := range parent.rules {
+			scope.rules[path] = append(scope.rules[path], rules...)
+		}
+	}
+	return scope
+}
+
+// child 字段 name 中的结构体(或数组、切片、map 中的结构体)继承的配置规则
+func (sc *ruleScope) child(name string) *ruleScope {
+	if sc == nil {
+		return nil
+	}
+	prefix := name + VALIDATOR_PATH_SPLIT
+	var child *ruleScope
+	for path, rules := range sc.rules {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		if child == nil {
+			child = &ruleScope{mode: sc.mode, rules: make(map[string][]string)}
+		}
+		child.rules[path[len(prefix):]] = rules
+	}
+	return child
+}
+
+// fieldRule 按配置调整字段的校验规则
+func (sc *ruleScope) fieldRule(name string, tag string) string {
+	if sc == nil {
+		return tag
+	}
+	rules, ok := sc.rules[name]
+	if !ok {
+		return tag
+	}
+	rule := strings.Join(rules, VALIDATOR_MUTIPLE_SPLIT)
+	if sc.mode == RuleModeOverride || tag == "" {
+		return rule
+	}
+	return tag + VALIDATOR_MUTIPLE_SPLIT + rule
+}
+
+// RegisterRuleTypes 注册可在规则配置中引用的结构体类型
+func (v *Validator) RegisterRuleTypes(types ...interface{}) *Validator {
+	for _, t := range types {
+		rt := reflect.TypeOf(t)
+		if rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+		v.ruleTypes[rt.Name()] = rt
+		v.ruleTypes[rt.String()] = rt
+	}
+	return v
+}
+
+// LoadRuleFile 从 JSON 文件加载规则，重复调用即重新加载
+func (v *Validator) LoadRuleFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return v.LoadRules(data)
+}
+
+// LoadRules 加载 JSON 规则，规则全部编译通过后才原子替换当前规则，出错时保留原规则
+func (v *Validator) LoadRules(data []byte) error {
+	var config RuleConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	set, err := v.compileRuleConfig(config)
+	if err != nil {
+		return err
+	}
+	v.ruleSet.Store(set)
+	return nil
+}
+
+func (v *Validator) compileRuleConfig(config RuleConfig) (*ruleSet, error) {
+	set := &ruleSet{mode: config.Mode, fields: make(map[reflect.Type]map[string]string)}
+	switch set.mode {
+	case "":
+		set.mode = RuleModeMerge
+	case RuleModeMerge, RuleModeOverride:
+	default:
+		return nil, fmt.Errorf(trans(ValidRuleMode), config.Mode)
+	}
+	// 按名称排序，出错时的信息是确定的
+	typeNames := make([]string, 0, len(config.Rules))
+	for typeName := range config.Rules {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+	for _, typeName := range typeNames {
+		rt, ok := v.ruleTypes[typeName]
+		if !ok {
+			return nil, fmt.Errorf(trans(ValidRuleType), typeName)
+		}
+		fields := config.Rules[typeName]
+		paths := make([]string, 0, len(fields))
+		for path := range fields {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			rule := fields[path]
+			sf, fieldPath, err := resolveFieldPath(rt, path)
+			if err != nil {
+				return nil, err
+			}
+			if errs := v.CheckTag(rule, v.TypeClassOf(sf.Type)); len(errs) > 0 {
+				if te, ok := errs[0].(*TagError); ok {
+					te.Path = typeName + VALIDATOR_PATH_SPLIT + path
+				}
+				return nil, errs[0]
+			}
+			if set.fields[rt] == nil {
+				set.fields[rt] = make(map[string]string)
+			}
+			// 嵌入结构体的字段可直接用字段名或带嵌入结构体名的路径，两者指向同一字段时规则依次生效
+			if prev, ok := set.fields[rt][fieldPath]; ok {
+				rule = prev + VALIDATOR_MUTIPLE_SPLIT + rule
+			}
+			set.fields[rt][fieldPath] = rule
+		}
+	}
+	return set, nil
+}
+
+// resolveFieldPath 按字段路径找到字段，返回校验时遍历到该字段的完整路径(嵌入结构体的字段带上嵌入字段名)
+// 经过数组、切片和 map 时取元素类型
+func resolveFieldPath(rt reflect.Type, path string) (reflect.StructField, string, error) {
+	var fullPath []string
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		sf, ok := rt.FieldByName(name)
+		if !ok {
+			return sf, "", fmt.Errorf(trans(ValidRuleField), rt, path)
+		}
+		for j := range sf.Index {
+			fullPath = append(fullPath, rt.FieldByIndex(sf.Index[:j+1]).Name)
+		}
+		if i == len(names)-1 {
+			return sf, strings.Join(fullPath, VALIDATOR_PATH_SPLIT), nil
+		}
+		rt = sf.Type
+		for rt.Kind() == reflect.Slice || rt.Kind() == reflect.Array || rt.Kind() == reflect.Map {
+			rt = rt.Elem()
+		}
+		if rt.Kind() != reflect.Struct {
+			return sf, "", fmt.Errorf(trans(ValidRuleField), rt, path)
+		}
+	}
+	return reflect.StructField{}, "", fmt.Errorf(trans(ValidRuleField), rt, path)
+}
+
+// loadedRules 当前加载的规则，未加载时为 nil
+func (v *Validator) loadedRules() *ruleSet {
+	set, _ := v.ruleSet.Load().(*ruleSet)
+	return set
+}
@@ -0,0 +1,78 @@
+package validators
+
+import (
+	"database/sql/driver"
+	"reflect"
+)
+
+// CustomTypeFunc 将自定义类型的字段转为基础类型的值，返回 nil 表示空值(只校验 required)
+type CustomTypeFunc func(field reflect.Value) interface{}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// valuerError driver.Valuer 取值失败
+type valuerError struct {
+	err error
+}
+
+// valuerTypeFunc 通过 driver.Valuer 取值，用于 sql.NullString 等类型，取值失败时返回 valuerError
+func valuerTypeFunc(field reflect.Value) interface{} {
+	val, err := field.Interface().(driver.Valuer).Value()
+	if err != nil {
+		return valuerError{err}
+	}
+	return val
+}
+
+// customValue 按注册的 CustomTypeFunc 或 driver.Valuer 转换字段值，ok 表示字段为自定义类型
+// driver.Valuer 取值失败时返回 err
+func (v *Validator) customValue(fv reflect.Value) (cv reflect.Value, ok bool, err error) {
+	if !fv.IsValid() || !fv.CanInterface() {
+		return
+	}
+	fn, ok := v.customTypeFuncs[fv.Type()]
+	if !ok {
+		if !fv.Type().Implements(valuerType) {
+			return
+		}
+		fn, ok = valuerTypeFunc, true
+	}
+	if (fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface) && fv.IsNil() {
+		return reflect.Value{}, true, nil
+	}
+	switch val := fn(fv).(type) {
+	case nil:
+	case valuerError:
+		err = val.err
+	default:
+		cv = reflect.ValueOf(val)
+	}
+	return
+}
+
+// customType 自定义类型转换后的类型，ok 表示 rt 为自定义类型，无法确定转换后的类型时 ct 为 nil
+// sql.NullString 等含 Valid bool 字段的类型取另一个字段的类型，其余类型按零值转换的结果判断
+func (v *Validator) customType(rt reflect.Type) (ct reflect.Type, ok bool) {
+	fn, ok := v.customTypeFuncs[rt]
+	if !ok {
+		if !rt.Implements(valuerType) {
+			return
+		}
+		fn, ok = valuerTypeFunc, true
+	}
+	if rt.Kind() == reflect.Struct && rt.NumField() == 2 {
+		for i := 0; i < 2; i++ {
+			if sf := rt.Field(i); sf.Name == "Valid" && sf.Type.Kind() == reflect.Bool {
+				return rt.Field(1 - i).Type, true
+			}
+		}
+	}
+	if rt.Kind() != reflect.Ptr && rt.Kind() != reflect.Interface {
+		switch val := fn(reflect.Zero(rt)).(type) {
+		case nil, valuerError:
+		default:
+			ct = reflect.TypeOf(val)
+		}
+	}
+	return
+}
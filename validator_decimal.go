@@ -0,0 +1,227 @@
+package validators
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// 精确小数校验，数值按十进制比较，不经过 float64
+// 支持数字字符串、整数、浮点数及 math/big 的 Int、Rat、Float
+
+// decimal 校验未通过的检查项
+const (
+	checkPrecision = "precision"
+	checkScale     = "scale"
+)
+
+// decimal 超过该位数仍无法整除的分数视为无限小数
+const maxDecimalScale = 100
+
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+)
+
+var decimalCompareMessages = map[string]string{
+	opEq:  ValidDecimalEq,
+	opLt:  ValidDecimalLt,
+	opLte: ValidDecimalLte,
+	opGt:  ValidDecimalGt,
+	opGte: ValidDecimalGte,
+}
+
+// decimalRule 为 math/big 类型及 json.Number 提供精确比较，其余类型交给 next
+func decimalRule(op string, next FuncCtx) FuncCtx {
+	return func(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+		if !isBigType(ft) && ft != jsonNumberType {
+			return next(ft, fv, title, params...)
+		}
+		if len(params) != 1 {
+			return fmt.Errorf("参数个数有误")
+		}
+		v, ok := decimalValue(fv)
+		if !ok {
+			return fmt.Errorf(trans(ValidIsDecimal), title, fmt.Sprint(fv))
+		}
+		p, ok := new(big.Rat).SetString(params[0])
+		if !ok {
+			return fmt.Errorf(trans(ValidDecimalParam), params[0])
+		}
+		if !compareRat(op, v.Cmp(p)) {
+			err = fmt.Errorf(trans(decimalCompareMessages[op]), title, params[0])
+		}
+		return
+	}
+}
+
+// isDecimal 小数的总位数和小数位数，decimal=12,2 同数据库 DECIMAL(12,2)，整数部分最多 10 位
+func isDecimal(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if len(params) != 2 {
+		return fmt.Errorf("参数个数有误")
+	}
+	precision, scale := int(asInt(params[0])), int(asInt(params[1]))
+	s, ok := decimalString(fv)
+	if !ok {
+		return fmt.Errorf(trans(ValidIsDecimal), title, fmt.Sprint(fv))
+	}
+	s = strings.TrimLeft(s, "+-")
+	intPart, fracPart := s, ""
+	if num := strings.Index(s, "."); num != -1 {
+		intPart, fracPart = s[:num], s[num+1:]
+	}
+	intPart = strings.TrimLeft(intPart, "0")
+
+	var checks []string
+	if len(intPart) > precision-scale {
+		checks = append(checks, checkPrecision)
+	}
+	if len(fracPart) > scale {
+		checks = append(checks, checkScale)
+	}
+	if len(checks) > 0 {
+		msg := fmt.Sprintf(trans(ValidDecimalDigits), title, precision-scale, scale)
+		err = newFieldError(title, "decimal", msg, checks...)
+	}
+	return
+}
+
+// isBetween 精确的数值范围(含端点)，between=0.01,99999.99，"_" 表示不限
+func isBetween(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if len(params) != 2 {
+		return fmt.Errorf("参数个数有误")
+	}
+	v, ok := decimalValue(fv)
+	if !ok {
+		return fmt.Errorf(trans(ValidIsDecimal), title, fmt.Sprint(fv))
+	}
+	for i, param := range params {
+		if param == VALIDATOR_IGNORE_SIGN {
+			continue
+		}
+		p, ok := new(big.Rat).SetString(param)
+		if !ok {
+			return fmt.Errorf(trans(ValidDecimalParam), param)
+		}
+		if (i == 0 && v.Cmp(p) < 0) || (i == 1 && v.Cmp(p) > 0) {
+			return fmt.Errorf(trans(ValidBetween), title, params[0], params[1])
+		}
+	}
+	return
+}
+
+// isMultipleOf 数值为参数的整数倍，multiple_of=0.01
+func isMultipleOf(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if len(params) != 1 {
+		return fmt.Errorf("参数个数有误")
+	}
+	v, ok := decimalValue(fv)
+	if !ok {
+		return fmt.Errorf(trans(ValidIsDecimal), title, fmt.Sprint(fv))
+	}
+	p, ok := new(big.Rat).SetString(params[0])
+	if !ok || p.Sign() == 0 {
+		return fmt.Errorf(trans(ValidDecimalParam), params[0])
+	}
+	if !new(big.Rat).Quo(v, p).IsInt() {
+		err = fmt.Errorf(trans(ValidMultipleOf), title, params[0])
+	}
+	return
+}
+
+func compareRat(op string, cmp int) bool {
+	switch op {
+	case opEq:
+		return cmp == 0
+	case opLt:
+		return cmp < 0
+	case opLte:
+		return cmp <= 0
+	case opGt:
+		return cmp > 0
+	case opGte:
+		return cmp >= 0
+	}
+	return false
+}
+
+// isBigType math/big 的 Int、Rat、Float 及其指针
+func isBigType(ft reflect.Type) bool {
+	if ft.Kind() == reflect.Ptr {
+		ft = ft.Elem()
+	}
+	return ft == bigIntType || ft == bigRatType || ft == bigFloatType
+}
+
+// decimalValue 将字段值转为精确的有理数，json.Number 可以是科学计数法
+func decimalValue(fv reflect.Value) (*big.Rat, bool) {
+	if fv.IsValid() && fv.Type() == jsonNumberType {
+		return new(big.Rat).SetString(fv.String())
+	}
+	s, ok := decimalString(fv)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Rat).SetString(s)
+}
+
+// decimalString 字段值的十进制字符串，浮点数取最短的精确表示，无限小数返回 false
+func decimalString(fv reflect.Value) (s string, ok bool) {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return
+		}
+		fv = fv.Elem()
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		s = fv.String()
+		return s, numericRegex.MatchString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(fv.Uint(), 10), true
+	case reflect.Float32:
+		return strconv.FormatFloat(fv.Float(), 'f', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'f', -1, 64), true
+	}
+	// 未导出字段中的 math/big 类型无法读取
+	if !fv.CanInterface() {
+		return
+	}
+	if !fv.CanAddr() {
+		tmp := reflect.New(fv.Type()).Elem()
+		tmp.Set(fv)
+		fv = tmp
+	}
+	switch x := fv.Addr().Interface().(type) {
+	case *big.Int:
+		return x.String(), true
+	case *big.Float:
+		if x.IsInf() {
+			return
+		}
+		return x.Text('f', -1), true
+	case *big.Rat:
+		if x.IsInt() {
+			return x.Num().String(), true
+		}
+		// 分母只含因子 2 和 5 时为有限小数
+		scaled := new(big.Rat).Set(x)
+		ten := big.NewRat(10, 1)
+		for scale := 1; scale <= maxDecimalScale; scale++ {
+			scaled.Mul(scaled, ten)
+			if scaled.IsInt() {
+				return x.FloatString(scale), true
+			}
+		}
+	}
+	return
+}
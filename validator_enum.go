@@ -0,0 +1,114 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 枚举校验，in=@name 引用 RegisterEnum 注册的枚举
+// in 不带参数时自动识别实现 IsValid() bool 或 Values() 方法的类型
+
+// VALIDATOR_ENUM_SIGN in 参数中引用命名枚举的前缀
+const VALIDATOR_ENUM_SIGN = "@"
+
+// RegisterEnum 注册命名枚举，供 in=@name 使用
+func (v *Validator) RegisterEnum(name string, values ...interface{}) *Validator {
+	enum := make([]string, 0, len(values))
+	for _, val := range values {
+		rv := reflect.ValueOf(val)
+		enum = append(enum, fmt.Sprint(parseReflectV(rv, rv.Kind())))
+	}
+	v.mu.Lock()
+	v.enums[name] = enum
+	v.mu.Unlock()
+	return v
+}
+
+// enum 查找 RegisterEnum 注册的枚举
+func (v *Validator) enum(name string) (values []string, ok bool) {
+	v.mu.RLock()
+	values, ok = v.enums[name]
+	v.mu.RUnlock()
+	return
+}
+
+// isIn 展开 in 参数中的命名枚举，无参数时使用字段类型的 IsValid() 或 Values()
+func (v *Validator) isIn(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if len(params) == 0 {
+		return v.isEnumType(ft, fv, title)
+	}
+	var allowed []string
+	for _, param := range params {
+		if !strings.HasPrefix(param, VALIDATOR_ENUM_SIGN) {
+			allowed = append(allowed, param)
+			continue
+		}
+		name := strings.TrimPrefix(param, VALIDATOR_ENUM_SIGN)
+		enum, ok := v.enum(name)
+		if !ok {
+			return fmt.Errorf(trans(ValidEnumNotExist), name)
+		}
+		allowed = append(allowed, enum...)
+	}
+	return isIn(ft, fv, title, allowed...)
+}
+
+// isEnumType 按 IsValid() bool 或 Values() 校验枚举类型的字段，数组、切片、map 校验每个元素
+func (v *Validator) isEnumType(ft reflect.Type, fv reflect.Value, title string) (err error) {
+	var vals []reflect.Value
+	switch ft.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fv.Len(); i++ {
+			vals = append(vals, fv.Index(i))
+		}
+	case reflect.Map:
+		for _, key := range fv.MapKeys() {
+			vals = append(vals, fv.MapIndex(key))
+		}
+	default:
+		vals = append(vals, fv)
+	}
+	for _, val := range vals {
+		if !val.CanInterface() {
+			return &TagError{Tag: "in", Msg: fmt.Sprintf(trans(ValidEnumUnexported), title)}
+		}
+		allowed, hasValues := enumValues(val)
+		if checker, ok := val.Interface().(interface{ IsValid() bool }); ok {
+			if checker.IsValid() {
+				continue
+			}
+			if hasValues {
+				return fmt.Errorf(trans(ValidIn), val, allowed)
+			}
+			return fmt.Errorf(trans(ValidEnum), title, val)
+		}
+		if !hasValues {
+			return &TagError{Tag: "in", Msg: fmt.Sprintf(trans(ValidEnumMethod), title, val.Type())}
+		}
+		if err = isIn(val.Type(), val, title, allowed...); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// enumValues 调用类型的 Values() 方法取全部枚举值，返回值须为数组或切片
+func enumValues(fv reflect.Value) (values []string, ok bool) {
+	method := fv.MethodByName("Values")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return
+	}
+	list := method.Call(nil)[0]
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return
+	}
+	for i := 0; i < list.Len(); i++ {
+		item := list.Index(i)
+		if item.Kind() == reflect.Interface {
+			item = item.Elem()
+		}
+		values = append(values, fmt.Sprint(parseReflectV(item, item.Kind())))
+	}
+	return values, true
+}
@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 )
@@ -14,24 +18,65 @@ var (
 
 type FuncCtx func(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error)
 
+// StructFuncCtx 可访问字段所在结构体 sv 的校验函数，用于字段间比较
+type StructFuncCtx func(sv reflect.Value, ft reflect.Type, fv reflect.Value, title string, params ...string) (err error)
+
 var defaultValidator = map[string]FuncCtx{
-	"required": hasValue,
-	"len":      hasLengthOf,
-	"min":      hasMinOf,
-	"max":      hasMaxOf,
-	"eq":       isEq,
-	"lt":       isLt,
-	"lte":      isLte,
-	"gt":       isGt,
-	"gte":      isGte,
-	"email":    isEmail,
-	"number":   isNumber,
-	"phone":    isPhone,
-	"ipv4":     isIPv4,
-	"ipv6":     isIPv6,
-	"ip":       isIP,
-	"in":       isIn,
-	"unique":   isUnique,
+	"required":      hasValue,
+	"len":           hasLengthOf,
+	"len_runes":     hasRuneLengthOf,
+	"len_bytes":     hasByteLengthOf,
+	"len_graphemes": hasGraphemeLengthOf,
+	"min":           decimalRule(opGte, hasMinOf),
+	"max":           decimalRule(opLte, hasMaxOf),
+	"eq":            decimalRule(opEq, isEq),
+	"lt":            decimalRule(opLt, isLt),
+	"lte":           decimalRule(opLte, isLte),
+	"gt":            decimalRule(opGt, isGt),
+	"gte":           decimalRule(opGte, isGte),
+	"email":         isEmail,
+	"uuid":          isUUID,
+	"number":        isNumber,
+	"phone":         isPhone,
+	"e164":          isE164,
+	"landline":      isLandline,
+	"uscc":          isUSCC,
+	"orgcode":       isOrgCode,
+	"taxno":         isTaxNo,
+	"luhn":          isLuhn,
+	"bankcard":      isBankCard,
+	"credit_card":   isCreditCard,
+	"iban":          isIBAN,
+	"contains":      isContains,
+	"contains_i":    isContainsFold,
+	"containsany":   isContainsAny,
+	"containsany_i": isContainsAnyFold,
+	"excludes":      isExcludes,
+	"excludes_i":    isExcludesFold,
+	"excludesall":   isExcludesAll,
+	"startswith":    isStartsWith,
+	"startswith_i":  isStartsWithFold,
+	"endswith":      isEndsWith,
+	"endswith_i":    isEndsWithFold,
+	"lowercase":     isLowercase,
+	"uppercase":     isUppercase,
+	"notblank":      isNotBlank,
+	"duration":      isDuration,
+	"date":          isDate,
+	"weekday":       isWeekday,
+	"timeofday":     isTimeOfDay,
+	"decimal":       isDecimal,
+	"between":       isBetween,
+	"multiple_of":   isMultipleOf,
+	"ipv4":          isIPv4,
+	"ipv6":          isIPv6,
+	"ip":            isIP,
+	"in":            isIn,
+	"unique":        isUnique,
+	"unique_i":      isUniqueI,
+	"jsontype":      isJSONType,
+	"jsonenum":      isJSONEnum,
+	"present":       isPresent,
 	//"datetime": isDatetie,
 	//"url":      isUrl,
 }
@@ -67,7 +112,7 @@ func isEq(ft reflect.Type, fv reflect.Value, title string, params ...string) (er
 		panic(fmt.Sprintf("Bad field type %T", fv.Interface()))
 	}
 	if flag {
-		err = fmt.Errorf("不等于")
+		err = fmt.Errorf(trans(boundMessage(opEq, ft)), title, param)
 	}
 	return
 }
@@ -101,16 +146,11 @@ func isLt(ft reflect.Type, fv reflect.Value, title string, params ...string) (er
 		p := asFloat(param)
 		flag = fv.Float() < p
 
-	case reflect.Struct:
-		if fv.Type() == timeType {
-			flag = fv.Interface().(time.Time).Before(time.Now().UTC())
-		}
-
 	default:
 		panic(fmt.Sprintf("Bad field type %T", fv.Interface()))
 	}
 	if !flag {
-		err = fmt.Errorf("%s不小于%s", title, param)
+		err = fmt.Errorf(trans(boundMessage(opLt, ft)), title, param)
 	}
 	return
 
@@ -147,17 +187,11 @@ func isLte(ft reflect.Type, fv reflect.Value, title string, params ...string) (e
 		p := asFloat(param)
 		flag = fv.Float() <= p
 
-	case reflect.Struct:
-		if fv.Type() == timeType {
-			now := time.Now().UTC()
-			t := fv.Interface().(time.Time)
-			flag = t.Before(now) || t.Equal(now)
-		}
 	default:
 		panic(fmt.Sprintf("Bad field type %T", fv.Interface()))
 	}
 	if !flag {
-		err = fmt.Errorf("%s大于%s", title, param)
+		err = fmt.Errorf(trans(boundMessage(opLte, ft)), title, param)
 	}
 	return
 }
@@ -191,16 +225,11 @@ func isGt(ft reflect.Type, fv reflect.Value, title string, params ...string) (er
 		p := asFloat(param)
 		flag = fv.Float() > p
 
-	case reflect.Struct:
-		if fv.Type() == timeType {
-			flag = fv.Interface().(time.Time).After(time.Now().UTC())
-		}
-
 	default:
 		panic(fmt.Sprintf("Bad field type %T", fv.Interface()))
 	}
 	if !flag {
-		err = fmt.Errorf("%s不大于%s", title, param)
+		err = fmt.Errorf(trans(boundMessage(opGt, ft)), title, param)
 	}
 	return
 }
@@ -235,33 +264,47 @@ func isGte(ft reflect.Type, fv reflect.Value, title string, params ...string) (e
 		p := asFloat(param)
 		flag = fv.Float() >= p
 
-	case reflect.Struct:
-		if fv.Type() == timeType {
-			now := time.Now().UTC()
-			t := fv.Interface().(time.Time)
-			flag = t.After(now) || t.Equal(now)
-		}
 	default:
 		panic(fmt.Sprintf("Bad field type %T", fv.Interface()))
 	}
 	if !flag {
-		err = fmt.Errorf("%s小于%s", title, param)
+		err = fmt.Errorf(trans(boundMessage(opGte, ft)), title, param)
 	}
 	return
 }
 
-// hasLengthOf
+// hasLengthOf 字符串按字符(rune)计算长度
 func hasLengthOf(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return hasLength(ft, fv, title, utf8.RuneCountInString, params...)
+}
+
+// hasRuneLengthOf 同 len，显式按字符(rune)计算字符串长度
+func hasRuneLengthOf(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return hasLength(ft, fv, title, utf8.RuneCountInString, params...)
+}
+
+// hasByteLengthOf 按字节计算字符串长度，与数据库 VARCHAR 字节长度一致
+func hasByteLengthOf(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return hasLength(ft, fv, title, func(s string) int { return len(s) }, params...)
+}
+
+// hasGraphemeLengthOf 按用户感知字符(字素簇)计算字符串长度，emoji、国旗等算作一个字符
+func hasGraphemeLengthOf(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return hasLength(ft, fv, title, graphemeCount, params...)
+}
+
+// hasLength 校验长度或数值范围，strLen 为字符串长度的计算方式
+func hasLength(ft reflect.Type, fv reflect.Value, title string, strLen func(s string) int, params ...string) (err error) {
 	var vInt int64
 	var vFloat float64
 	if len(params) < 1 {
-		err = fmt.Errorf("参数个数有误")
+		return fmt.Errorf("参数个数有误")
 	}
 	kind := ft.Kind()
 	switch ft.Kind() {
 	case reflect.String:
 		kind = reflect.Int32
-		vInt = int64(utf8.RuneCountInString(fv.String()))
+		vInt = int64(strLen(fv.String()))
 		//fmt.Println("LenString:", fv.String(), kind, vInt)
 	case reflect.Slice, reflect.Map, reflect.Array:
 		kind = reflect.Int32
@@ -283,59 +326,78 @@ func hasLengthOf(ft reflect.Type, fv reflect.Value, title string, params ...stri
 		return
 	}
 
-	if len(params) == 1 {
+	// cmp 参数与值比较，参数大于值时为 1
+	cmp := func(param string) int {
 		if kind == reflect.Float64 {
-			if asFloat(params[0]) != vFloat {
-				err = fmt.Errorf("不等于%f", asFloat(params[0]))
-			}
-		} else if kind == reflect.Int64 {
-			if asInt(params[0]) != vInt {
-				err = fmt.Errorf("不等于%d", asInt(params[0]))
-			}
-		} else {
-			if asInt(params[0]) != vInt {
-				err = fmt.Errorf("长度不等于%d", asInt(params[0]))
+			p := asFloat(param)
+			if p > vFloat {
+				return 1
+			} else if p < vFloat {
+				return -1
 			}
+			return 0
 		}
-	} else if len(params) >= 1 {
-
-		if params[0] != VALIDATOR_IGNORE_SIGN {
-			//fmt.Println("INT32:", fv.String(), kind, vInt, asInt(params[0]))
-			if kind == reflect.Float64 {
-				if asFloat(params[0]) > vFloat {
-					err = fmt.Errorf("小于%f", asFloat(params[0]))
-				}
-			} else if kind == reflect.Int64 {
-				if asInt(params[0]) > vInt {
-					err = fmt.Errorf("小于%d", asInt(params[0]))
-				}
-			} else {
-
-				if asInt(params[0]) > vInt {
-					err = fmt.Errorf("长度小于%d", asInt(params[0]))
-				}
-			}
+		p := asInt(param)
+		if p > vInt {
+			return 1
+		} else if p < vInt {
+			return -1
 		}
-		if params[1] != VALIDATOR_IGNORE_SIGN {
-
-			if kind == reflect.Float64 {
-				if asFloat(params[1]) < vFloat {
-					err = fmt.Errorf("大于%f", asFloat(params[1]))
-				}
-			} else if kind == reflect.Int64 {
-				if asInt(params[1]) < vInt {
-					err = fmt.Errorf("大于%d", asInt(params[1]))
-				}
-			} else {
-				if asInt(params[1]) < vInt {
-					err = fmt.Errorf("长度大于%d", asInt(params[1]))
-				}
-			}
+		return 0
+	}
+	// 字符串、数组等为长度，数值为取值范围，与 OpenAPI 导出的信息一致
+	keys := lengthMessages
+	if kind != reflect.Int32 {
+		keys = rangeMessages
+	}
+	if len(params) == 1 {
+		if cmp(params[0]) != 0 {
+			err = fmt.Errorf(trans(keys[0]), title, params[0])
+		}
+		return
+	}
+	min, max := params[0], params[1]
+	if (min != VALIDATOR_IGNORE_SIGN && cmp(min) > 0) || (max != VALIDATOR_IGNORE_SIGN && cmp(max) < 0) {
+		switch {
+		case min != VALIDATOR_IGNORE_SIGN && max != VALIDATOR_IGNORE_SIGN:
+			err = fmt.Errorf(trans(keys[1]), title, min, max)
+		case min != VALIDATOR_IGNORE_SIGN:
+			err = fmt.Errorf(trans(keys[2]), title, min)
+		default:
+			err = fmt.Errorf(trans(keys[3]), title, max)
 		}
 	}
 	return
 }
 
+// len 规则的错误信息，依次为等于、范围、下限、上限
+var (
+	lengthMessages = [4]string{ValidLengthEq, ValidLength, ValidLengthGte, ValidLengthLte}
+	rangeMessages  = [4]string{ValidDecimalEq, ValidBetween, ValidDecimalGte, ValidDecimalLte}
+)
+
+// boundMessage 比较规则的错误信息，字符串、数组等比较长度，其余比较数值
+// eq 对字符串比较的是值
+func boundMessage(op string, ft reflect.Type) string {
+	switch ft.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return lengthCompareMessages[op]
+	case reflect.String:
+		if op != opEq {
+			return lengthCompareMessages[op]
+		}
+	}
+	return decimalCompareMessages[op]
+}
+
+var lengthCompareMessages = map[string]string{
+	opEq:  ValidLengthEq,
+	opLt:  ValidLengthLt,
+	opLte: ValidLengthLte,
+	opGt:  ValidLengthGt,
+	opGte: ValidLengthGte,
+}
+
 // hasMinOf
 func hasMinOf(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
 	return isGte(ft, fv, title, params...)
@@ -349,7 +411,7 @@ func hasMaxOf(ft reflect.Type, fv reflect.Value, title string, params ...string)
 // hasValue
 func hasValue(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
 	if isZeroValue(fv) {
-		err = fmt.Errorf("不能为空")
+		err = fmt.Errorf(trans(ValidRequired), title)
 	}
 	return
 
@@ -394,7 +456,7 @@ func isIn(ft reflect.Type, fv reflect.Value, title string, params ...string) (er
 	}
 	for _, valI := range vals {
 		if !InArray(parseReflectV(valI, kind), argsI) {
-			err = fmt.Errorf("%v不在指定范围11:%v", valI, params)
+			err = fmt.Errorf(trans(ValidIn), valI, params)
 		}
 	}
 	return
@@ -403,19 +465,208 @@ func isIn(ft reflect.Type, fv reflect.Value, title string, params ...string) (er
 // IsEmail is the validation function for validating if the current field's value is a valid email address.
 func isEmail(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
 	if !emailRegex.MatchString(fv.String()) {
-		err = fmt.Errorf("非Email:%s", fv.String())
+		err = fmt.Errorf(trans(ValidIsEmail), title)
+	}
+	return
+}
+
+// isUUID RFC 4122 格式的 UUID，不区分大小写
+func isUUID(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if !uUIDRFC4122Regex.MatchString(fv.String()) {
+		err = fmt.Errorf(trans(ValidIsUUID), title)
 	}
 	return
 }
 
-// isPhone
+// compilePattern 取 regex 规则的正则，/pattern/ 为内联正则，编译结果缓存，其余为 RegisterPattern 注册的名称
+func (v *Validator) compilePattern(name string) (re *regexp.Regexp, err error) {
+	if len(name) >= 2 && strings.HasPrefix(name, "/") && strings.HasSuffix(name, "/") {
+		if cached, ok := v.inlinePatterns.Load(name); ok {
+			return cached.(*regexp.Regexp), nil
+		}
+		if re, err = regexp.Compile(name[1 : len(name)-1]); err != nil {
+			return nil, fmt.Errorf(trans(ValidPatternError), name, err)
+		}
+		v.inlinePatterns.Store(name, re)
+		return
+	}
+	re, ok := v.pattern(name)
+	if !ok {
+		return nil, fmt.Errorf(trans(ValidPatternNotExist), name)
+	}
+	return
+}
+
+// isRegex 正则校验，regex=name 使用 RegisterPattern 注册的正则，regex=/pattern/ 为内联正则
+// 内联正则中的";"需转义为"\;"(结构体 tag 中写作"\\;")，","可不转义
+func (v *Validator) isRegex(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if len(params) == 0 {
+		return fmt.Errorf("参数个数有误")
+	}
+	name := strings.Join(params, VALIDATOR_RANGE_SPLIT)
+	re, err := v.compilePattern(name)
+	if err != nil {
+		return
+	}
+	if !re.MatchString(fv.String()) {
+		err = fmt.Errorf(trans(ValidIsRegex), title, name)
+	}
+	return
+}
+
+// isPhone 手机号码，参数为地区代码(phone=HK)，可指定多个，默认为 CN
 func isPhone(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
-	if !phoneRegex.MatchString(fv.String()) {
-		err = fmt.Errorf(trans(ValidIsPhone), fv.String())
+	regions := params
+	if len(regions) == 0 {
+		regions = []string{defaultPhoneRegion}
+	}
+	for _, region := range regions {
+		meta, ok := lookupPhoneMeta(region)
+		if !ok {
+			return &TagError{Tag: "phone=" + region, Msg: fmt.Sprintf(trans(ValidPhoneRegion), region)}
+		}
+		if meta.match(fv.String()) {
+			return
+		}
 	}
+	err = fmt.Errorf(trans(ValidIsMobile), title)
 	return
 }
 
+// isE164 E.164 格式的国际电话号码，如 +8613800138000
+func isE164(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if !e164Regex.MatchString(fv.String()) {
+		err = fmt.Errorf(trans(ValidIsE164), title, fv.String())
+	}
+	return
+}
+
+// isLandline 国内固定电话，格式为 区号-号码-分机号，如 010-62345678、0571-8888888-123
+func isLandline(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if !landlineRegex.MatchString(fv.String()) {
+		err = fmt.Errorf(trans(ValidIsLandline), title, fv.String())
+	}
+	return
+}
+
+// isUSCC 统一社会信用代码(GB 32100-2015)
+func isUSCC(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if !checkUSCC(fv.String()) {
+		err = fmt.Errorf(trans(ValidIsUSCC), title, fv.String())
+	}
+	return
+}
+
+// isOrgCode 组织机构代码(GB 11714-1997)，可带"-"分隔校验码
+func isOrgCode(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if !checkOrgCode(fv.String()) {
+		err = fmt.Errorf(trans(ValidIsOrgCode), title, fv.String())
+	}
+	return
+}
+
+// isTaxNo 纳税人识别号，支持 15 位旧税号、18 位统一社会信用代码和 20 位个人税号
+func isTaxNo(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if !checkTaxNo(fv.String()) {
+		err = fmt.Errorf(trans(ValidIsTaxNo), title, fv.String())
+	}
+	return
+}
+
+// 统一社会信用代码字符集，不含 I、O、Z、S、V
+const usccCharset = "0123456789ABCDEFGHJKLMNPQRTUWXY"
+
+var usccWeights = []int{1, 3, 9, 27, 19, 26, 16, 17, 20, 29, 25, 13, 8, 24, 10, 30, 28}
+
+// checkUSCC 校验统一社会信用代码及其第 18 位校验码
+func checkUSCC(code string) bool {
+	if len(code) != 18 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		n := strings.IndexByte(usccCharset, code[i])
+		if n < 0 {
+			return false
+		}
+		sum += n * usccWeights[i]
+	}
+	check := (31 - sum%31) % 31
+	if code[17] != usccCharset[check] {
+		return false
+	}
+	// 第 9-17 位为组织机构代码
+	return checkOrgCode(code[8:17])
+}
+
+var orgCodeWeights = []int{3, 7, 9, 10, 5, 8, 4, 2}
+
+// checkOrgCode 校验组织机构代码，格式为 8 位本体代码加 1 位校验码，中间可带"-"
+func checkOrgCode(code string) bool {
+	if len(code) == 10 && code[8] == '-' {
+		code = code[:8] + code[9:]
+	}
+	if len(code) != 9 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 8; i++ {
+		c := code[i]
+		switch {
+		case c >= '0' && c <= '9':
+			sum += int(c-'0') * orgCodeWeights[i]
+		case c >= 'A' && c <= 'Z':
+			sum += int(c-'A'+10) * orgCodeWeights[i]
+		default:
+			return false
+		}
+	}
+	var check byte
+	switch n := 11 - sum%11; n {
+	case 10:
+		check = 'X'
+	case 11:
+		check = '0'
+	default:
+		check = byte('0' + n)
+	}
+	return code[8] == check
+}
+
+var idCardWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+// checkIDCard 校验 18 位居民身份证号码的校验码(ISO 7064 MOD 11-2)
+func checkIDCard(code string) bool {
+	if len(code) != 18 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+		sum += int(code[i]-'0') * idCardWeights[i]
+	}
+	return code[17] == "10X98765432"[sum%11]
+}
+
+// checkTaxNo 校验纳税人识别号
+//
+//	15 位: 6 位行政区划码 + 9 位组织机构代码
+//	18 位: 统一社会信用代码
+//	20 位: 18 位身份证号码 + 2 位顺序码
+func checkTaxNo(code string) bool {
+	switch len(code) {
+	case 15:
+		return numberRegex.MatchString(code[:6]) && checkOrgCode(code[6:])
+	case 18:
+		return checkUSCC(code)
+	case 20:
+		return checkIDCard(code[:18]) && numberRegex.MatchString(code[18:])
+	}
+	return false
+}
+
 // isNumber
 func isNumber(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
 	switch ft.Kind() {
@@ -432,7 +683,7 @@ func isNumber(ft reflect.Type, fv reflect.Value, title string, params ...string)
 // isIPv4
 func isIPv4(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
 	ip := net.ParseIP(fv.String())
-	if ip == nil || ip.To4() != nil {
+	if ip == nil || ip.To4() == nil {
 		err = fmt.Errorf("非IPv4")
 	}
 	return
@@ -441,7 +692,7 @@ func isIPv4(ft reflect.Type, fv reflect.Value, title string, params ...string) (
 // isIPv6
 func isIPv6(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
 	ip := net.ParseIP(fv.String())
-	if ip == nil || ip.To16() != nil {
+	if ip == nil || ip.To4() != nil {
 		err = fmt.Errorf("非IPv6")
 	}
 	return
@@ -458,54 +709,96 @@ func isIP(ft reflect.Type, fv reflect.Value, title string, params ...string) (er
 
 // isUnique
 func isUnique(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
-	var flag bool
-	v := reflect.ValueOf(1)
+	return uniqueRule(ft, fv, title, false, params...)
+}
+
+// isUniqueI 忽略大小写的唯一值校验
+func isUniqueI(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return uniqueRule(ft, fv, title, true, params...)
+}
+
+// uniqueRule 数组、切片或 map 的值唯一，unique=ID 按结构体(或结构体指针)元素的 ID 字段比较
+// 未通过时返回的 FieldError.Duplicates 为重复元素的下标(map 为键)，按字段比较时跳过 nil 元素
+func uniqueRule(ft reflect.Type, fv reflect.Value, title string, fold bool, params ...string) (err error) {
+	if len(params) > 1 {
+		return fmt.Errorf("参数个数有误")
+	}
+	var positions []string
+	var vals []reflect.Value
 	switch ft.Kind() {
 	case reflect.Slice, reflect.Array:
-
-		switch fv.Type().Elem().Kind() {
-		case reflect.String:
-			m := make(map[string]int)
-			for i := 0; i < fv.Len(); i++ {
-				m[fv.Index(i).String()] = 1
-			}
-			flag = fv.Len() != len(m)
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			m := make(map[int64]int)
-			for i := 0; i < fv.Len(); i++ {
-				m[fv.Index(i).Int()] = 1
-			}
-			flag = fv.Len() != len(m)
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-			m := make(map[uint64]int)
-			for i := 0; i < fv.Len(); i++ {
-				m[fv.Index(i).Uint()] = 1
-			}
-			flag = fv.Len() != len(m)
-		case reflect.Float32, reflect.Float64:
-			m := make(map[float64]int)
-			for i := 0; i < fv.Len(); i++ {
-				m[fv.Index(i).Float()] = 1
-			}
-			flag = fv.Len() != len(m)
+		for i := 0; i < fv.Len(); i++ {
+			positions = append(positions, strconv.Itoa(i))
+			vals = append(vals, fv.Index(i))
 		}
-		//m := reflect.MakeMap(reflect.MapOf(fv.Type().Elem(), v.Type()))
-		//for i := 0; i < fv.Len(); i++ {
-		//	m.SetMapIndex(fv.Index(i), v)
-		//}
-		//flag = fv.Len() != m.Len()
 	case reflect.Map:
-		m := reflect.MakeMap(reflect.MapOf(fv.Type().Elem(), v.Type()))
-		for _, k := range fv.MapKeys() {
-			m.SetMapIndex(fv.MapIndex(k), v)
+		keys := fv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, key := range keys {
+			positions = append(positions, fmt.Sprint(key))
+			vals = append(vals, fv.MapIndex(key))
 		}
-		flag = fv.Len() != m.Len()
 	default:
-		panic(fmt.Sprintf("唯一值校验类型不支持 %T", fv.Interface()))
+		return fmt.Errorf(trans(ValidUniqueType), title)
 	}
-	if flag {
-		err = fmt.Errorf("非唯一值")
+
+	seen := make(map[interface{}]bool, len(vals))
+	var duplicates []string
+	for i, val := range vals {
+		key, ok := uniqueKey(val, fold, params...)
+		if !ok {
+			return fmt.Errorf(trans(ValidUniqueType), title)
+		}
+		if key == nil && len(params) > 0 {
+			continue
+		}
+		if seen[key] {
+			duplicates = append(duplicates, positions[i])
+		}
+		seen[key] = true
+	}
+	if len(duplicates) > 0 {
+		rule := "unique"
+		if fold {
+			rule = "unique_i"
+		}
+		e := newFieldError(title, rule, fmt.Sprintf(trans(ValidUnique), title, duplicates))
+		e.Duplicates = duplicates
+		err = e
 	}
 	return
+}
 
+// uniqueKey 元素用于比较的键，指定字段时取结构体字段，不可比较的值按 %#v 比较
+func uniqueKey(val reflect.Value, fold bool, field ...string) (key interface{}, ok bool) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil, true
+		}
+		val = val.Elem()
+	}
+	if len(field) > 0 {
+		if val.Kind() != reflect.Struct {
+			return
+		}
+		if val = val.FieldByName(field[0]); !val.IsValid() {
+			return
+		}
+		return uniqueKey(val, fold)
+	}
+	if val.Kind() == reflect.String {
+		return foldString(val.String(), fold), true
+	}
+	if checkNumber(val.Kind()) || checkBool(val.Kind()) {
+		return parseReflectV(val, val.Kind()), true
+	}
+	if !val.CanInterface() {
+		return
+	}
+	if val.Type().Comparable() {
+		return val.Interface(), true
+	}
+	return fmt.Sprintf("%#v", val.Interface()), true
 }
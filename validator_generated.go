@@ -0,0 +1,110 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// 供 cmd/validatorsgen 生成的代码使用，生成的代码直接访问字段，不再遍历结构体和读取 tag
+// 基础类型字段上的内置规则由生成的代码直接校验，错误经 Fail 生成；其余规则由 Check 按反射校验
+// 两种方式的结果均与 Validator.Struct 一致
+
+// FieldRule 单个字段的校验规则
+type FieldRule struct {
+	title string
+	rule  string
+}
+
+// NewFieldRule 创建字段规则，规则在校验时按 Validator 编译，与 tag 中的规则一致
+func NewFieldRule(title string, rule string) *FieldRule {
+	return &FieldRule{title: title, rule: rule}
+}
+
+// CheckZero 零值是否仍需校验
+func (r *FieldRule) CheckZero(v *Validator) bool {
+	return v.allowEmpty || v.checksZero(r.rule)
+}
+
+// Continue 同一字段的规则未通过后是否继续校验其余规则，与 SetLazy 的设置一致
+func (r *FieldRule) Continue(v *Validator) bool {
+	return v.lazy
+}
+
+// Fail 生成的代码中内置规则未通过时的错误，rule 为未通过的规则名，key 为错误信息(如 ValidRequired)，args 为信息的参数
+func (r *FieldRule) Fail(path string, rule string, key string, args ...interface{}) error {
+	err := newFieldError(r.title, rule, fmt.Sprintf(trans(key), args...))
+	err.Path = path
+	return err
+}
+
+// Check 校验字段，parent 为字段所在结构体的指针，field 为字段的指针，path 为字段路径
+// next 表示是否继续校验字段中的结构体，字段未通过、零值跳过或为自定义类型时为 false
+func (r *FieldRule) Check(v *Validator, parent interface{}, field interface{}, path string) (errs []error, next bool) {
+	fv := reflect.ValueOf(field).Elem()
+	ft := fv.Type()
+	cv, custom, err := v.customValue(fv)
+	if err != nil {
+		errs = []error{newFieldError(r.title, r.rule, fmt.Sprintf(trans(ValidValuer), r.title, err))}
+		setErrorPath(errs, path)
+		return errs, false
+	}
+	if custom {
+		fv, ft = cv, nil
+		if fv.IsValid() {
+			ft = fv.Type()
+		}
+	}
+	if isZeroValue(fv) && !r.CheckZero(v) {
+		return
+	}
+	errs = v.validateRule(reflect.ValueOf(parent).Elem(), ft, fv, r.title, r.rule)
+	setErrorPath(errs, path)
+	return errs, len(errs) == 0 && !custom
+}
+
+// Nested 递归校验字段中的结构体，field 为字段的指针，lazy 为 true 时返回第一个错误
+func (v *Validator) Nested(field interface{}, lazy bool, path string) []error {
+	fv := reflect.ValueOf(field).Elem()
+	if _, custom, _ := v.customValue(fv); custom {
+		return nil
+	}
+	return v.validateChildren(fv, lazy, &sync.Map{}, path, nil)
+}
+
+// RuleStep 规则中 ";" 连接的单条规则
+type RuleStep struct {
+	Name   string
+	Params []string
+}
+
+// RuleSteps 按 tag 的语法拆分只由 ";" 连接的规则，供 validatorsgen 生成直接校验的代码
+// 规则含 "|"、"!"、分组或无法解析时 ok 为 false；别名在运行时注册，不展开
+func RuleSteps(rule string) (steps []RuleStep, ok bool) {
+	p := &ruleParser{s: rule}
+	node, err := p.parseAnd()
+	if err != nil || p.pos < len(p.s) {
+		return nil, false
+	}
+	leaves := []*ruleNode{node}
+	if node.kind == ruleAnd {
+		leaves = node.children
+	}
+	for _, leaf := range leaves {
+		if leaf.kind != ruleLeaf {
+			return nil, false
+		}
+		steps = append(steps, RuleStep{Name: leaf.name, Params: leaf.params})
+	}
+	return steps, true
+}
+
+// IsEmail 同 email 规则，供生成的代码使用
+func IsEmail(s string) bool {
+	return emailRegex.MatchString(s)
+}
+
+// IsUUID 同 uuid 规则，供生成的代码使用
+func IsUUID(s string) bool {
+	return uUIDRFC4122Regex.MatchString(s)
+}
@@ -0,0 +1,157 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VALIDATOR_ANY_SIGN 路径中匹配数组全部元素或 map 全部值的通配符
+const VALIDATOR_ANY_SIGN = "*"
+
+// ValidateMap 按路径校验 map，用于解码后的 JSON 等没有结构体的数据
+//
+//	v.ValidateMap(data, map[string]string{
+//		"name":         "required;len=1,5",
+//		"address.city": "required",
+//		"items.*.sku":  "required",
+//	})
+//
+// 路径用 "." 分隔，"*" 匹配数组全部元素或 map 全部值，不存在的路径只校验 required
+// 错误为 FieldError，Path 为展开后的路径，如 items.0.sku
+func (v *Validator) ValidateMap(data map[string]interface{}, rules map[string]string) (errs []error) {
+	return v.validatePaths(reflect.ValueOf(data), rules)
+}
+
+// validatePaths 按路径规则校验 root，路径 "" 表示 root 本身
+func (v *Validator) validatePaths(root reflect.Value, rules map[string]string) (errs []error) {
+	paths := make([]string, 0, len(rules))
+	for path := range rules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		rule := rules[path]
+		var names []string
+		if path != "" {
+			names = strings.Split(path, VALIDATOR_PATH_SPLIT)
+		}
+		for _, field := range expandMapPath(root, reflect.Value{}, "", names) {
+			fv := field.value
+			// 值为 interface{} 时取实际的值
+			for fv.Kind() == reflect.Interface {
+				fv = fv.Elem()
+			}
+			if isZeroValue(fv) && !v.allowEmpty && !v.checksZero(rule) {
+				continue
+			}
+			var ft reflect.Type
+			if fv.IsValid() {
+				ft = fv.Type()
+			}
+			title := field.path
+			if title == "" {
+				title = "$"
+			}
+			var errArr []error
+			if err := v.kindError(rule, ft, title); err != nil {
+				errArr = []error{err}
+			} else {
+				errArr = v.validateRule(field.parent, ft, fv, title, rule)
+			}
+			setErrorPath(errArr, field.path)
+			errs = append(errs, errArr...)
+		}
+	}
+	return
+}
+
+// kindError map 中的值类型不固定，值的类型不适用于内置规则时返回 FieldError，不调用规则
+func (v *Validator) kindError(rule string, ft reflect.Type, title string) error {
+	if ft == nil {
+		return nil
+	}
+	class := v.TypeClassOf(ft)
+	if class == TypeAny {
+		return nil
+	}
+	// 规则有误时由 validateRule 返回 TagError
+	node, err := v.compileRule(rule)
+	if err != nil {
+		return nil
+	}
+	var leaf *ruleNode
+	walkRule(node, func(n *ruleNode) {
+		if spec, ok := ruleSpecs[n.name]; ok && leaf == nil && len(spec.types) > 0 && !spec.allows(class) {
+			leaf = n
+		}
+	})
+	if leaf == nil {
+		return nil
+	}
+	return newFieldError(title, leaf.name, fmt.Sprintf(trans(ValidRuleKind), leaf.name, class))
+}
+
+// mapField 按路径展开的字段，parent 为字段所在的 map 或数组
+type mapField struct {
+	path   string
+	value  reflect.Value
+	parent reflect.Value
+}
+
+// expandMapPath 展开路径，"*" 按下标或键的顺序展开
+func expandMapPath(current reflect.Value, parent reflect.Value, prefix string, names []string) []mapField {
+	if len(names) == 0 {
+		return []mapField{{path: prefix, value: current, parent: parent}}
+	}
+	for current.Kind() == reflect.Interface || current.Kind() == reflect.Ptr {
+		if current.IsNil() {
+			current = reflect.Value{}
+			break
+		}
+		current = current.Elem()
+	}
+	name, rest := names[0], names[1:]
+	var fields []mapField
+	switch current.Kind() {
+	case reflect.Map:
+		if name == VALIDATOR_ANY_SIGN {
+			keys := current.MapKeys()
+			sort.Slice(keys, func(i, j int) bool {
+				return keys[i].String() < keys[j].String()
+			})
+			for _, key := range keys {
+				fields = append(fields, expandMapPath(current.MapIndex(key), current, joinPath(prefix, key.String()), rest)...)
+			}
+			return fields
+		}
+		var next reflect.Value
+		if current.Type().Key().Kind() == reflect.String {
+			next = current.MapIndex(reflect.ValueOf(name).Convert(current.Type().Key()))
+		}
+		return expandMapPath(next, current, joinPath(prefix, name), rest)
+	case reflect.Slice, reflect.Array:
+		if name == VALIDATOR_ANY_SIGN {
+			for i := 0; i < current.Len(); i++ {
+				fields = append(fields, expandMapPath(current.Index(i), current, joinPath(prefix, strconv.Itoa(i)), rest)...)
+			}
+			return fields
+		}
+		i, err := strconv.Atoi(name)
+		if err != nil {
+			return nil
+		}
+		var next reflect.Value
+		if i >= 0 && i < current.Len() {
+			next = current.Index(i)
+		}
+		return expandMapPath(next, current, joinPath(prefix, name), rest)
+	}
+	// 路径经过的值不是 map 或数组时不展开，路径不存在时 "*" 不展开
+	if current.IsValid() || name == VALIDATOR_ANY_SIGN {
+		return nil
+	}
+	return expandMapPath(reflect.Value{}, current, joinPath(prefix, name), rest)
+}
@@ -0,0 +1,212 @@
+package validators
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 密码策略检查项，password=min:8,upper:1,lower:1,digit:1,special:1,norepeat:3,entropy:60
+const (
+	passwordMin      = "min"
+	passwordMax      = "max"
+	passwordUpper    = "upper"
+	passwordLower    = "lower"
+	passwordDigit    = "digit"
+	passwordSpecial  = "special"
+	passwordNoRepeat = "norepeat"
+	passwordEntropy  = "entropy"
+	passwordBanned   = "banned"
+)
+
+// passwordDefaultPolicy password 规则未带参数时使用的策略
+var passwordDefaultPolicy = []string{"min:8"}
+
+var passwordMessages = map[string]string{
+	passwordMin:      ValidPasswordMin,
+	passwordMax:      ValidPasswordMax,
+	passwordUpper:    ValidPasswordUpper,
+	passwordLower:    ValidPasswordLower,
+	passwordDigit:    ValidPasswordDigit,
+	passwordSpecial:  ValidPasswordSpecial,
+	passwordNoRepeat: ValidPasswordNoRepeat,
+	passwordEntropy:  ValidPasswordEntropy,
+}
+
+// parsePasswordPolicy 解析 key:n 格式的密码策略，key 须为 passwordMessages 中的策略
+func parsePasswordPolicy(param string) (key string, n int, err error) {
+	num := strings.Index(param, ":")
+	if num != -1 {
+		key = param[:num]
+		n, err = strconv.Atoi(param[num+1:])
+	}
+	if _, ok := passwordMessages[key]; num == -1 || err != nil || !ok {
+		return "", 0, fmt.Errorf(trans(ValidPasswordPolicy), param)
+	}
+	return
+}
+
+// BannedPasswords 弱密码列表
+type BannedPasswords interface {
+	Contains(password string) bool
+}
+
+// bannedPasswordSet 忽略大小写的弱密码集合
+type bannedPasswordSet map[string]struct{}
+
+func (s bannedPasswordSet) Contains(password string) bool {
+	_, ok := s[strings.ToLower(password)]
+	return ok
+}
+
+// LoadBannedPasswords 从本地文件加载弱密码列表，每行一个，忽略空行和 # 开头的注释
+func LoadBannedPasswords(path string) (BannedPasswords, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	set := bannedPasswordSet{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		set[strings.ToLower(line)] = struct{}{}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// SetBannedPasswords 设置 password 规则使用的弱密码列表，nil 表示不检查
+func (v *Validator) SetBannedPasswords(list BannedPasswords) *Validator {
+	v.bannedPasswords = list
+	return v
+}
+
+// isPassword 密码强度，一次返回所有未满足的检查项
+func (v *Validator) isPassword(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	password := fv.String()
+	if len(params) == 0 {
+		params = passwordDefaultPolicy
+	}
+	stat := statPassword(password)
+
+	var checks []string
+	var details []string
+	for _, param := range params {
+		key, n, e := parsePasswordPolicy(param)
+		if e != nil {
+			return e
+		}
+		var pass bool
+		switch key {
+		case passwordMin:
+			pass = stat.length >= n
+		case passwordMax:
+			pass = stat.length <= n
+		case passwordUpper:
+			pass = stat.upper >= n
+		case passwordLower:
+			pass = stat.lower >= n
+		case passwordDigit:
+			pass = stat.digit >= n
+		case passwordSpecial:
+			pass = stat.special >= n
+		case passwordNoRepeat:
+			pass = stat.maxRepeat < n
+		case passwordEntropy:
+			pass = stat.entropy() >= float64(n)
+		default:
+			return fmt.Errorf(trans(ValidPasswordPolicy), param)
+		}
+		if !pass {
+			checks = append(checks, key)
+			details = append(details, fmt.Sprintf(trans(passwordMessages[key]), n))
+		}
+	}
+	if v.bannedPasswords != nil && v.bannedPasswords.Contains(password) {
+		checks = append(checks, passwordBanned)
+		details = append(details, trans(ValidPasswordBanned))
+	}
+
+	if len(checks) > 0 {
+		msg := fmt.Sprintf(trans(ValidIsPassword), title, strings.Join(details, ", "))
+		err = newFieldError(title, "password", msg, checks...)
+	}
+	return
+}
+
+// passwordStat 密码字符统计
+type passwordStat struct {
+	length    int
+	upper     int
+	lower     int
+	digit     int
+	special   int
+	other     int
+	maxRepeat int
+}
+
+func statPassword(password string) (stat passwordStat) {
+	var last rune
+	repeat := 0
+	for _, r := range password {
+		stat.length++
+		switch {
+		case r <= unicode.MaxASCII && unicode.IsUpper(r):
+			stat.upper++
+		case r <= unicode.MaxASCII && unicode.IsLower(r):
+			stat.lower++
+		case r <= unicode.MaxASCII && unicode.IsDigit(r):
+			stat.digit++
+		case r <= unicode.MaxASCII && unicode.IsPrint(r):
+			stat.special++
+		default:
+			stat.other++
+		}
+		if r == last {
+			repeat++
+		} else {
+			repeat = 1
+		}
+		if repeat > stat.maxRepeat {
+			stat.maxRepeat = repeat
+		}
+		last = r
+	}
+	return
+}
+
+// entropy 按字符集大小估算的熵值(bit): 长度 * log2(字符集大小)
+func (s passwordStat) entropy() float64 {
+	pool := 0
+	if s.lower > 0 {
+		pool += 26
+	}
+	if s.upper > 0 {
+		pool += 26
+	}
+	if s.digit > 0 {
+		pool += 10
+	}
+	if s.special > 0 {
+		pool += 33
+	}
+	if s.other > 0 {
+		pool += 100
+	}
+	if pool == 0 {
+		return 0
+	}
+	return float64(s.length) * math.Log2(float64(pool))
+}
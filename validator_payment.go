@@ -0,0 +1,191 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 银行卡、信用卡及 IBAN 校验未通过的检查项
+const (
+	checkFormat   = "format"
+	checkLength   = "length"
+	checkLuhn     = "luhn"
+	checkBrand    = "brand"
+	checkCountry  = "country"
+	checkChecksum = "checksum"
+)
+
+// cardBrands 卡组织的号段及长度
+var cardBrands = map[string]func(card string) bool{
+	"visa": func(card string) bool {
+		return card[0] == '4' && inArray(len(card), 13, 16, 19)
+	},
+	"mastercard": func(card string) bool {
+		if len(card) != 16 {
+			return false
+		}
+		p2, p4 := asInt(card[:2]), asInt(card[:4])
+		return (p2 >= 51 && p2 <= 55) || (p4 >= 2221 && p4 <= 2720)
+	},
+	"amex": func(card string) bool {
+		return len(card) == 15 && (strings.HasPrefix(card, "34") || strings.HasPrefix(card, "37"))
+	},
+	"unionpay": func(card string) bool {
+		return isUnionPay(card)
+	},
+}
+
+// ibanLengths 各国 IBAN 长度，未列出的国家只校验通用长度 15-34
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AT": 20, "BE": 16, "BG": 22, "CH": 21, "CY": 28, "CZ": 24,
+	"DE": 22, "DK": 18, "EE": 20, "ES": 24, "FI": 18, "FR": 27, "GB": 22, "GR": 27,
+	"HR": 21, "HU": 28, "IE": 22, "IS": 26, "IT": 27, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "MC": 27, "MT": 31, "NL": 18, "NO": 15, "PL": 28, "PT": 25, "RO": 24,
+	"SA": 24, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "TR": 26,
+}
+
+// isLuhn 数字串满足 Luhn 校验
+func isLuhn(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	s := fv.String()
+	if !numberRegex.MatchString(s) {
+		return newFieldError(title, "luhn", fmt.Sprintf(trans(ValidIsLuhn), title, s), checkFormat)
+	}
+	if !checkLuhnSum(s) {
+		return newFieldError(title, "luhn", fmt.Sprintf(trans(ValidIsLuhn), title, s), checkLuhn)
+	}
+	return
+}
+
+// isBankCard 银行卡号，16-19 位数字，允许空格分隔
+// 银联卡(62 开头)不强制 Luhn 校验，bankcard=unionpay 要求必须为银联卡
+func isBankCard(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	for _, param := range params {
+		if param != "unionpay" {
+			return fmt.Errorf(trans(ValidCardBrand), param)
+		}
+	}
+	card := normalizeCard(fv.String())
+	var checks []string
+	switch {
+	case !numberRegex.MatchString(card):
+		checks = append(checks, checkFormat)
+	case len(card) < 16 || len(card) > 19:
+		checks = append(checks, checkLength)
+	default:
+		unionPay := isUnionPay(card)
+		if len(params) > 0 && !unionPay {
+			checks = append(checks, checkBrand)
+		}
+		if !unionPay && !checkLuhnSum(card) {
+			checks = append(checks, checkLuhn)
+		}
+	}
+	if len(checks) > 0 {
+		msg := fmt.Sprintf(trans(ValidIsBankCard), title, fv.String(), strings.Join(checks, ","))
+		err = newFieldError(title, "bankcard", msg, checks...)
+	}
+	return
+}
+
+// isCreditCard 信用卡号，参数为允许的卡组织(credit_card=visa,mastercard)，默认允许所有内置卡组织
+// 与 bankcard 一致，银联卡不强制 Luhn 校验
+func isCreditCard(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	card := normalizeCard(fv.String())
+	brands := params
+	if len(brands) == 0 {
+		brands = []string{"visa", "mastercard", "amex", "unionpay"}
+	}
+	var checks []string
+	if !numberRegex.MatchString(card) {
+		checks = append(checks, checkFormat)
+	} else {
+		matched := false
+		for _, brand := range brands {
+			match, ok := cardBrands[strings.ToLower(brand)]
+			if !ok {
+				return fmt.Errorf(trans(ValidCardBrand), brand)
+			}
+			if match(card) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			checks = append(checks, checkBrand)
+		}
+		if !isUnionPay(card) && !checkLuhnSum(card) {
+			checks = append(checks, checkLuhn)
+		}
+	}
+	if len(checks) > 0 {
+		msg := fmt.Sprintf(trans(ValidIsCreditCard), title, fv.String(), strings.Join(checks, ","))
+		err = newFieldError(title, "credit_card", msg, checks...)
+	}
+	return
+}
+
+// isIBAN 国际银行账号，校验格式、国家长度及 mod-97 校验码
+func isIBAN(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	iban := strings.ToUpper(strings.Replace(fv.String(), " ", "", -1))
+	var checks []string
+	if !ibanRegex.MatchString(iban) {
+		checks = append(checks, checkFormat)
+	} else {
+		if l, ok := ibanLengths[iban[:2]]; ok && l != len(iban) {
+			checks = append(checks, checkCountry)
+		}
+		if !checkIBANSum(iban) {
+			checks = append(checks, checkChecksum)
+		}
+	}
+	if len(checks) > 0 {
+		msg := fmt.Sprintf(trans(ValidIsIBAN), title, fv.String(), strings.Join(checks, ","))
+		err = newFieldError(title, "iban", msg, checks...)
+	}
+	return
+}
+
+// normalizeCard 去掉卡号中的空格和"-"
+func normalizeCard(card string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(card)
+}
+
+// isUnionPay 银联卡，62 开头的 16-19 位卡号
+func isUnionPay(card string) bool {
+	return strings.HasPrefix(card, "62") && len(card) >= 16 && len(card) <= 19
+}
+
+// checkLuhnSum Luhn 校验，s 必须为数字串
+func checkLuhnSum(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		n := int(s[i] - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// checkIBANSum 将前四位移到末尾，字母转为数字后按 mod-97 校验，余数应为 1
+func checkIBANSum(iban string) bool {
+	rearranged := iban[4:] + iban[:4]
+	mod := 0
+	for i := 0; i < len(rearranged); i++ {
+		c := rearranged[i]
+		if c >= 'A' && c <= 'Z' {
+			n := int(c-'A') + 10
+			mod = (mod*100 + n) % 97
+		} else {
+			mod = (mod*10 + int(c-'0')) % 97
+		}
+	}
+	return mod == 1
+}
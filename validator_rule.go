@@ -0,0 +1,409 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 规则组合，";" 表示且，"|" 表示或，"!" 表示非，括号用于分组，优先级 ! > | > ;
+//
+//	validate:"required;email|phone"
+//	validate:"!in=admin,root"
+//	validate:"(email|phone);len=5,64"
+//
+// 参数中的 ";"、"|"、"("、")" 需转义为 "\;" 等(结构体 tag 中写作 "\\;")
+// regex=/pattern/ 内联正则中只需转义 ";"
+
+// rawParamRules 参数不按 "," 拆分、只以 ";" 或未配对的 ")" 结束的规则
+var rawParamRules = map[string]bool{
+	"expr": true,
+}
+
+const (
+	VALIDATOR_OR_SIGN    = "|"
+	VALIDATOR_NOT_SIGN   = "!"
+	VALIDATOR_GROUP_OPEN = "("
+	VALIDATOR_GROUP_END  = ")"
+)
+
+// 规则节点类型
+const (
+	ruleLeaf = iota
+	ruleAnd
+	ruleOr
+	ruleNot
+	ruleAlias
+)
+
+// ruleNode 编译后的规则树
+type ruleNode struct {
+	kind     int
+	name     string   // 叶子节点的规则名或别名
+	params   []string // 叶子节点的参数
+	children []*ruleNode
+	text     string // 规则原文，用于错误信息
+}
+
+// compileRule 编译 tag 中的规则，结果按规则原文缓存
+func (v *Validator) compileRule(rulerString string) (node *ruleNode, err error) {
+	if cached, ok := v.rules.Load(rulerString); ok {
+		return cached.(*ruleNode), nil
+	}
+	// 解析及缓存期间持有读锁，RegisterAlias 清空缓存后不会再写入按旧别名编译的结果
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	p := &ruleParser{s: rulerString, aliases: v.aliases}
+	if node, err = p.parseAnd(); err != nil {
+		return nil, &TagError{Tag: rulerString, Msg: err.Error()}
+	}
+	if p.pos < len(p.s) {
+		return nil, &TagError{Tag: rulerString, Msg: fmt.Sprintf(trans(ValidRuleSyntax), rulerString, p.pos)}
+	}
+	v.rules.Store(rulerString, node)
+	return
+}
+
+// evalRule 按规则树校验字段
+func (v *Validator) evalRule(node *ruleNode, structValue reflect.Value, typeObj reflect.Type, typeValue reflect.Value, title string) (errs []error) {
+	switch node.kind {
+	case ruleAnd:
+		for _, child := range node.children {
+			errArr := v.evalRule(child, structValue, typeObj, typeValue, title)
+			if len(errArr) > 0 {
+				errs = append(errs, errArr...)
+				if v.lazy == false {
+					return
+				}
+			}
+		}
+	case ruleOr:
+		if name := v.missingRule(node); name != "" {
+			return []error{&TagError{Tag: name, Msg: fmt.Sprintf(trans(ValidNotExist), name)}}
+		}
+		var msgs, checks []string
+		for _, child := range node.children {
+			errArr := v.evalRule(child, structValue, typeObj, typeValue, title)
+			if len(errArr) == 0 {
+				return nil
+			}
+			msgs = append(msgs, errArr[0].Error())
+			checks = append(checks, child.text)
+		}
+		msg := fmt.Sprintf(trans(ValidAnyOf), title, strings.Join(msgs, "; "))
+		errs = append(errs, newFieldError(title, node.text, msg, checks...))
+	case ruleNot:
+		if name := v.missingRule(node); name != "" {
+			return []error{&TagError{Tag: name, Msg: fmt.Sprintf(trans(ValidNotExist), name)}}
+		}
+		// 空值不校验取反的规则
+		if !typeValue.IsValid() {
+			return
+		}
+		if len(v.evalRule(node.children[0], structValue, typeObj, typeValue, title)) == 0 {
+			msg := fmt.Sprintf(trans(ValidNot), title, node.children[0].text)
+			errs = append(errs, newFieldError(title, node.text, msg))
+		}
+	case ruleAlias:
+		var checks []string
+		for _, child := range aliasRules(node) {
+			errArr := v.evalRule(child, structValue, typeObj, typeValue, title)
+			if len(errArr) == 0 {
+				continue
+			}
+			if len(checks) == 0 {
+				errs = errArr[:1]
+			}
+			checks = append(checks, child.text)
+			if v.lazy == false {
+				break
+			}
+		}
+		if len(checks) == 0 {
+			return
+		}
+		msg := fmt.Sprintf(trans(ValidAlias), title, node.name)
+		if v.aliasDetail {
+			msg += ": " + errs[0].Error()
+		}
+		errs = []error{newFieldError(title, node.name, msg, checks...)}
+	default:
+		// 空值(如 sql.NullString 未赋值)只校验 required 和 present
+		if !typeValue.IsValid() && node.name != "required" && node.name != "present" {
+			return
+		}
+		// 判断验证规则是否存在
+		var err error
+		if validator, ok := v.validator[node.name]; ok {
+			// 验证规则
+			err = validator(typeObj, typeValue, title, node.params...)
+		} else if validator, ok := v.structValidator[node.name]; ok {
+			err = validator(structValue, typeObj, typeValue, title, node.params...)
+		} else {
+			err = &TagError{Tag: node.name, Msg: fmt.Sprintf(trans(ValidNotExist), node.name)}
+		}
+		switch err.(type) {
+		case nil:
+		case *FieldError, *TagError:
+			errs = append(errs, err)
+		default:
+			errs = append(errs, newFieldError(title, node.name, err.Error()))
+		}
+	}
+	return
+}
+
+// aliasRules 别名展开后的各条规则
+func aliasRules(node *ruleNode) []*ruleNode {
+	if child := node.children[0]; child.kind == ruleAnd {
+		return child.children
+	}
+	return node.children
+}
+
+// zeroRules 字段为零值时仍需校验的规则
+var zeroRules = map[string]bool{"required": true, "present": true}
+
+// checksZero 规则中是否含有 zeroRules 中的规则，别名按展开后的规则判断
+// 规则有误时返回 true，由校验返回规则错误
+func (v *Validator) checksZero(rulerString string) bool {
+	node, err := v.compileRule(rulerString)
+	if err != nil {
+		return true
+	}
+	return hasZeroRule(node)
+}
+
+func hasZeroRule(node *ruleNode) bool {
+	if node.kind == ruleLeaf {
+		return zeroRules[node.name]
+	}
+	for _, child := range node.children {
+		if hasZeroRule(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// missingRule 返回规则树中第一个未注册的规则名
+func (v *Validator) missingRule(node *ruleNode) string {
+	if node.kind == ruleLeaf {
+		if _, ok := v.validator[node.name]; ok {
+			return ""
+		}
+		if _, ok := v.structValidator[node.name]; ok {
+			return ""
+		}
+		return node.name
+	}
+	for _, child := range node.children {
+		if name := v.missingRule(child); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// ruleParser 规则的递归下降解析器
+type ruleParser struct {
+	s         string
+	pos       int
+	aliases   map[string]string
+	expanding []string // 正在展开的别名，用于检查循环引用
+}
+
+func (p *ruleParser) peek(sign string) bool {
+	return strings.HasPrefix(p.s[p.pos:], sign)
+}
+
+// parseAnd 解析 ";" 连接的规则
+func (p *ruleParser) parseAnd() (*ruleNode, error) {
+	return p.parseList(ruleAnd, VALIDATOR_MUTIPLE_SPLIT, p.parseOr)
+}
+
+// parseOr 解析 "|" 连接的规则
+func (p *ruleParser) parseOr() (*ruleNode, error) {
+	return p.parseList(ruleOr, VALIDATOR_OR_SIGN, p.parseUnary)
+}
+
+func (p *ruleParser) parseList(kind int, sep string, next func() (*ruleNode, error)) (*ruleNode, error) {
+	start := p.pos
+	node := &ruleNode{kind: kind}
+	for {
+		child, err := next()
+		if err != nil {
+			return nil, err
+		}
+		node.children = append(node.children, child)
+		if !p.peek(sep) {
+			break
+		}
+		p.pos += len(sep)
+	}
+	if len(node.children) == 1 {
+		return node.children[0], nil
+	}
+	node.text = p.s[start:p.pos]
+	return node, nil
+}
+
+// parseUnary 解析 "!" 取反、括号分组及单个规则
+func (p *ruleParser) parseUnary() (*ruleNode, error) {
+	start := p.pos
+	switch {
+	case p.peek(VALIDATOR_NOT_SIGN):
+		p.pos += len(VALIDATOR_NOT_SIGN)
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &ruleNode{kind: ruleNot, children: []*ruleNode{child}, text: p.s[start:p.pos]}, nil
+	case p.peek(VALIDATOR_GROUP_OPEN):
+		p.pos += len(VALIDATOR_GROUP_OPEN)
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(VALIDATOR_GROUP_END) {
+			return nil, fmt.Errorf(trans(ValidRuleSyntax), p.s, p.pos)
+		}
+		p.pos += len(VALIDATOR_GROUP_END)
+		if child.kind == ruleLeaf {
+			return child, nil
+		}
+		// 分组整体作为一个条件，错误信息中保留括号
+		group := *child
+		group.text = p.s[start:p.pos]
+		return &group, nil
+	}
+	return p.parseLeaf()
+}
+
+// parseLeaf 解析单个规则 name=params，参数遇到未转义的 ";"、"|"、")" 结束
+// 不带参数的别名展开为注册的规则
+func (p *ruleParser) parseLeaf() (*ruleNode, error) {
+	start := p.pos
+	for p.pos < len(p.s) && !p.peek(VALIDATOR_VALUE_SIGN) && !p.atEnd() {
+		p.pos++
+	}
+	node := &ruleNode{kind: ruleLeaf, name: p.s[start:p.pos]}
+	if !p.peek(VALIDATOR_VALUE_SIGN) {
+		node.text = node.name
+		if body, ok := p.aliases[node.name]; ok {
+			return p.expandAlias(node.name, body)
+		}
+		return node, nil
+	}
+	p.pos += len(VALIDATOR_VALUE_SIGN)
+	if rawParamRules[node.name] {
+		node.params = []string{p.rawParam()}
+		node.text = p.s[start:p.pos]
+		return node, nil
+	}
+	raw, ok := p.inlinePattern()
+	if !ok {
+		raw = p.params()
+	}
+	node.params = splitEscaped(raw, VALIDATOR_RANGE_SPLIT)
+	node.text = p.s[start:p.pos]
+	return node, nil
+}
+
+// expandAlias 解析别名对应的规则
+func (p *ruleParser) expandAlias(name string, body string) (*ruleNode, error) {
+	for _, expanding := range p.expanding {
+		if expanding == name {
+			return nil, fmt.Errorf(trans(ValidAliasCycle), name)
+		}
+	}
+	sub := &ruleParser{s: body, aliases: p.aliases, expanding: append(p.expanding[:len(p.expanding):len(p.expanding)], name)}
+	child, err := sub.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	if sub.pos < len(sub.s) {
+		return nil, fmt.Errorf(trans(ValidRuleSyntax), body, sub.pos)
+	}
+	return &ruleNode{kind: ruleAlias, name: name, children: []*ruleNode{child}, text: name}, nil
+}
+
+// atEnd 当前位置是否为规则的结束符
+func (p *ruleParser) atEnd() bool {
+	return p.peek(VALIDATOR_MUTIPLE_SPLIT) || p.peek(VALIDATOR_OR_SIGN) || p.peek(VALIDATOR_GROUP_END)
+}
+
+// params 读取参数，"\;"、"\|"、"\("、"\)" 转为字面量，其余的 "\" 原样保留
+func (p *ruleParser) params() string {
+	var buf strings.Builder
+	for p.pos < len(p.s) && !p.atEnd() {
+		if p.peek(VALIDATOR_ESCAPE_SIGN) && p.pos+1 < len(p.s) && strings.ContainsAny(p.s[p.pos+1:p.pos+2], ";|()") {
+			buf.WriteByte(p.s[p.pos+1])
+			p.pos += 2
+			continue
+		}
+		buf.WriteByte(p.s[p.pos])
+		p.pos++
+	}
+	return buf.String()
+}
+
+// rawParam 读取整段参数，引号内的字符及配对的括号不作为结束符，"\;" 转为 ";"
+func (p *ruleParser) rawParam() string {
+	var buf strings.Builder
+	depth := 0
+	var quote byte
+	for ; p.pos < len(p.s); p.pos++ {
+		c := p.s[p.pos]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && p.pos+1 < len(p.s) {
+				buf.WriteByte(c)
+				p.pos++
+				c = p.s[p.pos]
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case p.peek(VALIDATOR_ESCAPE_SIGN + VALIDATOR_MUTIPLE_SPLIT):
+			p.pos++
+			c = p.s[p.pos]
+		case p.peek(VALIDATOR_MUTIPLE_SPLIT):
+			return buf.String()
+		case p.peek(VALIDATOR_GROUP_OPEN):
+			depth++
+		case p.peek(VALIDATOR_GROUP_END):
+			if depth == 0 {
+				return buf.String()
+			}
+			depth--
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+// inlinePattern 读取 /.../ 形式的内联正则，结尾的 "/" 之后须为结束符，正则中只有 "\;" 需转义
+// "\/" 等转义的字符不作为结尾
+func (p *ruleParser) inlinePattern() (raw string, ok bool) {
+	if !p.peek("/") {
+		return
+	}
+	for end := p.pos + 1; end < len(p.s); end++ {
+		if p.s[end] == '\\' {
+			end++
+			continue
+		}
+		if p.s[end] != '/' {
+			continue
+		}
+		rest := &ruleParser{s: p.s, pos: end + 1}
+		if rest.pos == len(p.s) || rest.atEnd() {
+			raw = strings.Replace(p.s[p.pos:end+1], VALIDATOR_ESCAPE_SIGN+VALIDATOR_MUTIPLE_SPLIT, VALIDATOR_MUTIPLE_SPLIT, -1)
+			p.pos = end + 1
+			return raw, true
+		}
+	}
+	return
+}
@@ -0,0 +1,162 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 字符串内容校验，参数按","拆分，字面量","写作"\,"(结构体 tag 中写作"\\,")
+// 带 _i 后缀的规则忽略大小写
+
+// isContains 包含所有参数，contains=foo,bar
+func isContains(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return containsRule(fv, title, false, params)
+}
+
+// isContainsFold 忽略大小写包含所有参数
+func isContainsFold(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return containsRule(fv, title, true, params)
+}
+
+// isContainsAny 至少包含一个参数，containsany=foo,bar
+func isContainsAny(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return containsAnyRule(fv, title, false, params)
+}
+
+// isContainsAnyFold 忽略大小写至少包含一个参数
+func isContainsAnyFold(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return containsAnyRule(fv, title, true, params)
+}
+
+// isExcludes 不包含任何参数，excludes=foo,bar
+func isExcludes(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return excludesRule(fv, title, false, params)
+}
+
+// isExcludesFold 忽略大小写不包含任何参数
+func isExcludesFold(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return excludesRule(fv, title, true, params)
+}
+
+// isExcludesAll 不包含参数中的任何字符，excludesall=<>&
+func isExcludesAll(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if len(params) == 0 {
+		return fmt.Errorf("参数个数有误")
+	}
+	chars := strings.Join(params, "")
+	if strings.ContainsAny(fv.String(), chars) {
+		err = fmt.Errorf(trans(ValidExcludesAll), title, chars)
+	}
+	return
+}
+
+// isStartsWith 以任一参数开头，startswith=http://,https://
+func isStartsWith(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return affixRule(fv, title, false, strings.HasPrefix, ValidStartsWith, params)
+}
+
+// isStartsWithFold 忽略大小写以任一参数开头
+func isStartsWithFold(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return affixRule(fv, title, true, strings.HasPrefix, ValidStartsWith, params)
+}
+
+// isEndsWith 以任一参数结尾，endswith=.jpg,.png
+func isEndsWith(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return affixRule(fv, title, false, strings.HasSuffix, ValidEndsWith, params)
+}
+
+// isEndsWithFold 忽略大小写以任一参数结尾
+func isEndsWithFold(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return affixRule(fv, title, true, strings.HasSuffix, ValidEndsWith, params)
+}
+
+// isLowercase 不含大写字母
+func isLowercase(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if s := fv.String(); s != strings.ToLower(s) {
+		err = fmt.Errorf(trans(ValidLowercase), title)
+	}
+	return
+}
+
+// isUppercase 不含小写字母
+func isUppercase(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if s := fv.String(); s != strings.ToUpper(s) {
+		err = fmt.Errorf(trans(ValidUppercase), title)
+	}
+	return
+}
+
+// isNotBlank 字符串不能只包含空白字符，其他类型同 required
+func isNotBlank(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	var blank bool
+	if ft.Kind() == reflect.String {
+		blank = strings.TrimSpace(fv.String()) == ""
+	} else {
+		blank = isZeroValue(fv)
+	}
+	if blank {
+		err = fmt.Errorf(trans(ValidNotBlank), title)
+	}
+	return
+}
+
+func containsRule(fv reflect.Value, title string, fold bool, params []string) (err error) {
+	if len(params) == 0 {
+		return fmt.Errorf("参数个数有误")
+	}
+	s := foldString(fv.String(), fold)
+	for _, param := range params {
+		if !strings.Contains(s, foldString(param, fold)) {
+			return fmt.Errorf(trans(ValidContains), title, param)
+		}
+	}
+	return
+}
+
+func containsAnyRule(fv reflect.Value, title string, fold bool, params []string) (err error) {
+	if len(params) == 0 {
+		return fmt.Errorf("参数个数有误")
+	}
+	s := foldString(fv.String(), fold)
+	for _, param := range params {
+		if strings.Contains(s, foldString(param, fold)) {
+			return
+		}
+	}
+	return fmt.Errorf(trans(ValidContainsAny), title, params)
+}
+
+func excludesRule(fv reflect.Value, title string, fold bool, params []string) (err error) {
+	if len(params) == 0 {
+		return fmt.Errorf("参数个数有误")
+	}
+	s := foldString(fv.String(), fold)
+	for _, param := range params {
+		if strings.Contains(s, foldString(param, fold)) {
+			return fmt.Errorf(trans(ValidExcludes), title, param)
+		}
+	}
+	return
+}
+
+func affixRule(fv reflect.Value, title string, fold bool, match func(s, affix string) bool, msgKey string, params []string) (err error) {
+	if len(params) == 0 {
+		return fmt.Errorf("参数个数有误")
+	}
+	s := foldString(fv.String(), fold)
+	for _, param := range params {
+		if match(s, foldString(param, fold)) {
+			return
+		}
+	}
+	return fmt.Errorf(trans(msgKey), title, params)
+}
+
+// foldString 忽略大小写比较时统一转为小写
+func foldString(s string, fold bool) string {
+	if fold {
+		return strings.ToLower(s)
+	}
+	return s
+}
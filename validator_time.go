@@ -0,0 +1,340 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 比较方式
+const (
+	opEq  = "eq"
+	opLt  = "lt"
+	opLte = "lte"
+	opGt  = "gt"
+	opGte = "gte"
+)
+
+// timeCompareRules 支持 time.Time 字段的比较规则
+var timeCompareRules = map[string]string{
+	"lt":  opLt,
+	"lte": opLte,
+	"gt":  opGt,
+	"gte": opGte,
+	"min": opGte,
+	"max": opLte,
+}
+
+var timeCompareMessages = map[string]string{
+	opLt:  ValidTimeLt,
+	opLte: ValidTimeLte,
+	opGt:  ValidTimeGt,
+	opGte: ValidTimeGte,
+}
+
+// timeParamLayouts 时间参数支持的绝对时间格式
+var timeParamLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// 相对时间 now+24h、now-18y、now+1y2mo，单位支持 y、mo、w、d 及 time.ParseDuration 的单位
+var (
+	relativeTimeRegex     = regexp.MustCompile(`^now((?:[+-]\d+(?:y|mo|w|d|h|ms|us|ns|m|s))*)$`)
+	relativeTimeTermRegex = regexp.MustCompile(`([+-])(\d+)(y|mo|w|d|h|ms|us|ns|m|s)`)
+)
+
+// timeRule 为 time.Time 字段提供与时间参数的比较，其余类型交给 next
+//
+//	gt=2020-01-01、lt=now、gte=now-18y
+func (v *Validator) timeRule(op string, next FuncCtx) FuncCtx {
+	return func(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+		t, ok := timeValue(fv)
+		if !ok {
+			if ft == timeType || (ft.Kind() == reflect.Ptr && ft.Elem() == timeType) {
+				return fmt.Errorf(trans(ValidTimeType), title)
+			}
+			return next(ft, fv, title, params...)
+		}
+		if len(params) != 1 {
+			return fmt.Errorf("参数个数有误")
+		}
+		p, err := parseTimeParam(params[0], v.now())
+		if err != nil {
+			return
+		}
+		return compareTime(op, t, p, title, params[0])
+	}
+}
+
+// isAfter 晚于指定时间，参数可以是绝对时间、相对时间或同一结构体中的时间字段
+//
+//	after=2020-01-01、after=now+24h、after=StartTime
+func (v *Validator) isAfter(sv reflect.Value, ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return v.compareTimeParam(opGt, sv, fv, title, params)
+}
+
+// isBefore 早于指定时间，参数同 after
+//
+//	before=now-18y、before=EndTime
+func (v *Validator) isBefore(sv reflect.Value, ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	return v.compareTimeParam(opLt, sv, fv, title, params)
+}
+
+func (v *Validator) compareTimeParam(op string, sv reflect.Value, fv reflect.Value, title string, params []string) (err error) {
+	if len(params) != 1 {
+		return fmt.Errorf("参数个数有误")
+	}
+	t, ok := timeValue(fv)
+	if !ok {
+		return fmt.Errorf(trans(ValidTimeType), title)
+	}
+	param := params[0]
+	var p time.Time
+	if field, found := structField(sv, param); found {
+		if p, ok = timeValue(field); !ok {
+			return fmt.Errorf(trans(ValidTimeType), param)
+		}
+	} else if p, err = parseTimeParam(param, v.now()); err != nil {
+		return
+	}
+	return compareTime(op, t, p, title, param)
+}
+
+func compareTime(op string, t time.Time, p time.Time, title string, param string) (err error) {
+	var flag bool
+	switch op {
+	case opLt:
+		flag = t.Before(p)
+	case opLte:
+		flag = !t.After(p)
+	case opGt:
+		flag = t.After(p)
+	case opGte:
+		flag = !t.Before(p)
+	}
+	if !flag {
+		err = fmt.Errorf(trans(timeCompareMessages[op]), title, param)
+	}
+	return
+}
+
+// timeValue 取 time.Time 或非空 *time.Time 字段的值，未导出的字段无法读取，视为类型错误
+func timeValue(fv reflect.Value) (t time.Time, ok bool) {
+	if fv.Kind() == reflect.Ptr && !fv.IsNil() {
+		fv = fv.Elem()
+	}
+	if fv.IsValid() && fv.Type() == timeType && fv.CanInterface() {
+		return fv.Interface().(time.Time), true
+	}
+	return
+}
+
+// structField 按名称取结构体字段
+func structField(sv reflect.Value, name string) (field reflect.Value, ok bool) {
+	if sv.Kind() != reflect.Struct {
+		return
+	}
+	field = sv.FieldByName(name)
+	return field, field.IsValid()
+}
+
+// parseTimeParam 解析时间参数，支持 now、相对时间及 timeParamLayouts 中的格式(UTC)
+func parseTimeParam(param string, now time.Time) (t time.Time, err error) {
+	if m := relativeTimeRegex.FindStringSubmatch(param); m != nil {
+		t = now
+		for _, term := range relativeTimeTermRegex.FindAllStringSubmatch(m[1], -1) {
+			n, _ := strconv.Atoi(term[2])
+			if term[1] == "-" {
+				n = -n
+			}
+			switch term[3] {
+			case "y":
+				t = t.AddDate(n, 0, 0)
+			case "mo":
+				t = t.AddDate(0, n, 0)
+			case "w":
+				t = t.AddDate(0, 0, 7*n)
+			case "d":
+				t = t.AddDate(0, 0, n)
+			default:
+				d, e := time.ParseDuration(strconv.Itoa(n) + term[3])
+				if e != nil {
+					return t, fmt.Errorf(trans(ValidTimeParam), param)
+				}
+				t = t.Add(d)
+			}
+		}
+		return
+	}
+	for _, layout := range timeParamLayouts {
+		if t, err = time.ParseInLocation(layout, param, time.UTC); err == nil {
+			return
+		}
+	}
+	return t, fmt.Errorf(trans(ValidTimeParam), param)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// timeOfDayLayouts 时刻支持的格式
+var timeOfDayLayouts = []string{"15:04", "15:04:05"}
+
+// isDuration time.Duration 或时长字符串的范围，duration=1m,2h，"_" 表示不限
+func isDuration(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if len(params) != 2 {
+		return fmt.Errorf("参数个数有误")
+	}
+	var d time.Duration
+	switch {
+	case ft == durationType:
+		d = time.Duration(fv.Int())
+	case ft.Kind() == reflect.String:
+		if d, err = time.ParseDuration(fv.String()); err != nil {
+			return fmt.Errorf(trans(ValidIsDuration), title, fv.String())
+		}
+	default:
+		return fmt.Errorf(trans(ValidIsDuration), title, fmt.Sprint(fv))
+	}
+	for i, param := range params {
+		if param == VALIDATOR_IGNORE_SIGN {
+			continue
+		}
+		p, e := time.ParseDuration(param)
+		if e != nil {
+			return fmt.Errorf(trans(ValidTimeParam), param)
+		}
+		if (i == 0 && d < p) || (i == 1 && d > p) {
+			return fmt.Errorf(trans(ValidDurationRange), title, params[0], params[1])
+		}
+	}
+	return
+}
+
+// isDate 只含日期的字符串，默认格式 2006-01-02，date=2006/01/02 指定格式
+func isDate(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	layout := "2006-01-02"
+	if len(params) > 0 {
+		layout = strings.Join(params, VALIDATOR_RANGE_SPLIT)
+	}
+	if _, e := time.Parse(layout, fv.String()); e != nil {
+		err = fmt.Errorf(trans(ValidIsDate), title, fv.String())
+	}
+	return
+}
+
+// isWeekday 星期几，weekday=mon,tue,Asia/Shanghai，含"/"的参数及 UTC、Local 为时区
+// 字段为 time.Time 或 2006-01-02 格式的日期字符串
+func isWeekday(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	allowed, names, loc, err := parseWeekdayParams(params)
+	if err != nil {
+		return
+	}
+	t, ok := timeValue(fv)
+	if !ok {
+		if ft.Kind() != reflect.String {
+			return fmt.Errorf(trans(ValidTimeType), title)
+		}
+		if t, err = time.ParseInLocation("2006-01-02", fv.String(), loc); err != nil {
+			return fmt.Errorf(trans(ValidIsDate), title, fv.String())
+		}
+	}
+	day := t.In(loc).Weekday()
+	for _, d := range allowed {
+		if d == day {
+			return
+		}
+	}
+	return fmt.Errorf(trans(ValidWeekday), title, names)
+}
+
+// parseWeekdayParams 解析 weekday 的参数，返回允许的星期及时区
+func parseWeekdayParams(params []string) (allowed []time.Weekday, names []string, loc *time.Location, err error) {
+	loc = time.UTC
+	for _, param := range params {
+		if strings.Contains(param, "/") || param == "UTC" || param == "Local" {
+			if loc, err = time.LoadLocation(param); err != nil {
+				return nil, nil, nil, fmt.Errorf(trans(ValidTimezone), param)
+			}
+			continue
+		}
+		day, ok := weekdays[strings.ToLower(param)]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf(trans(ValidWeekdayName), param)
+		}
+		allowed = append(allowed, day)
+		names = append(names, param)
+	}
+	if len(allowed) == 0 {
+		err = fmt.Errorf("参数个数有误")
+	}
+	return
+}
+
+// isTimeOfDay 时刻在指定区间内(含端点)，timeofday=09:00,18:00,Asia/Shanghai
+// 开始时刻晚于结束时刻表示跨午夜，如 22:00,06:00；字段为 time.Time 或 15:04 格式的字符串
+func isTimeOfDay(ft reflect.Type, fv reflect.Value, title string, params ...string) (err error) {
+	if len(params) != 2 && len(params) != 3 {
+		return fmt.Errorf("参数个数有误")
+	}
+	loc := time.UTC
+	if len(params) == 3 {
+		if loc, err = time.LoadLocation(params[2]); err != nil {
+			return fmt.Errorf(trans(ValidTimezone), params[2])
+		}
+	}
+	start, ok1 := parseTimeOfDay(params[0])
+	end, ok2 := parseTimeOfDay(params[1])
+	if !ok1 || !ok2 {
+		return fmt.Errorf(trans(ValidTimeParam), strings.Join(params[:2], VALIDATOR_RANGE_SPLIT))
+	}
+
+	var clock time.Duration
+	if t, ok := timeValue(fv); ok {
+		t = t.In(loc)
+		clock = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	} else if ft.Kind() == reflect.String {
+		var ok bool
+		if clock, ok = parseTimeOfDay(fv.String()); !ok {
+			return fmt.Errorf(trans(ValidIsTimeOfDay), title, fv.String())
+		}
+	} else {
+		return fmt.Errorf(trans(ValidTimeType), title)
+	}
+
+	var flag bool
+	if start <= end {
+		flag = clock >= start && clock <= end
+	} else {
+		flag = clock >= start || clock <= end
+	}
+	if !flag {
+		err = fmt.Errorf(trans(ValidTimeOfDay), title, params[0], params[1])
+	}
+	return
+}
+
+// parseTimeOfDay 解析时刻，返回距零点的时长
+func parseTimeOfDay(s string) (d time.Duration, ok bool) {
+	for _, layout := range timeOfDayLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, true
+		}
+	}
+	return
+}